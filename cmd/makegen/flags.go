@@ -1,11 +1,167 @@
 package main
 
-import "flag"
-
-// ParseFlags parses command-line flags
-func ParseFlags() {
-	flag.BoolVar(verbose, "verbose", false, "Enable verbose output")
-	flag.BoolVar(version_, "version", false, "Show version information")
-	flag.BoolVar(help, "help", false, "Show help message")
-	flag.Parse()
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/app"
+	"github.com/gaoubak/Makegen/internal/utils"
+)
+
+// options holds the flags of every subcommand; each command only registers
+// the ones it uses
+type options struct {
+	dir     string
+	verbose bool
+	name    string
+	force   bool
+	format  string
+	output  string
+	dryRun  bool
+}
+
+// command is a makegen subcommand
+type command struct {
+	name    string
+	args    string // positional arguments shown in the usage line
+	summary string
+	flags   func(fs *flag.FlagSet, opts *options)
+	run     func(a *app.App, opts *options, args []string, stdout io.Writer) error
+}
+
+// nameFlag registers --name, used when writing a Makefile without prompts
+func nameFlag(fs *flag.FlagSet, opts *options) {
+	fs.StringVar(&opts.name, "name", "", "Project name (default: existing PROJECT_NAME or directory name)")
+}
+
+// validateOptions rejects a --name or --output that would produce a
+// broken Makefile
+func validateOptions(opts *options) error {
+	if opts.name != "" {
+		if err := utils.ValidateProjectName(opts.name); err != nil {
+			return fmt.Errorf("invalid --name: %w", err)
+		}
+	}
+	if opts.output != "" && opts.output != "-" {
+		if err := utils.ValidateFilePath(opts.output); err != nil {
+			return fmt.Errorf("invalid --output: %w", err)
+		}
+	}
+	return nil
+}
+
+// outputFlags registers --output, and --dry-run for commands that write
+func outputFlags(dryRun bool) func(fs *flag.FlagSet, opts *options) {
+	return func(fs *flag.FlagSet, opts *options) {
+		fs.StringVar(&opts.output, "output", "", "File to write, e.g. GNUmakefile or makegen.mk, or - for stdout (default: Makefile)")
+		if dryRun {
+			fs.BoolVar(&opts.dryRun, "dry-run", false, "Print a diff against the existing file instead of writing")
+		}
+	}
+}
+
+var commands = []command{
+	{
+		name:    "init",
+		summary: "Create a Makefile interactively (default)",
+		flags:   outputFlags(true),
+		run: func(a *app.App, _ *options, _ []string, _ io.Writer) error {
+			return a.Run()
+		},
+	},
+	{
+		name:    "detect",
+		summary: "Print what was detected in the project",
+		flags: func(fs *flag.FlagSet, opts *options) {
+			fs.StringVar(&opts.format, "format", "text", "Output format: text, json or yaml")
+		},
+		run: func(a *app.App, opts *options, _ []string, stdout io.Writer) error {
+			return a.Detect(stdout, opts.format)
+		},
+	},
+	{
+		name:    "generate",
+		summary: "Write a Makefile from detected defaults without prompting",
+		flags: func(fs *flag.FlagSet, opts *options) {
+			nameFlag(fs, opts)
+			outputFlags(true)(fs, opts)
+			fs.BoolVar(&opts.force, "force", false, "Overwrite an existing Makefile")
+		},
+		run: func(a *app.App, opts *options, _ []string, _ io.Writer) error {
+			return a.Generate(opts.name, opts.force)
+		},
+	},
+	{
+		name:    "update",
+		summary: "Regenerate the Makefile, keeping hand-written targets",
+		flags: func(fs *flag.FlagSet, opts *options) {
+			nameFlag(fs, opts)
+			outputFlags(true)(fs, opts)
+		},
+		run: func(a *app.App, opts *options, _ []string, _ io.Writer) error {
+			return a.Update(opts.name)
+		},
+	},
+	{
+		name:    "check",
+		summary: "Exit with status 3 when the Makefile is out of date",
+		flags: func(fs *flag.FlagSet, opts *options) {
+			nameFlag(fs, opts)
+			outputFlags(false)(fs, opts)
+		},
+		run: func(a *app.App, opts *options, _ []string, stdout io.Writer) error {
+			return a.Check(stdout, opts.name)
+		},
+	},
+	{
+		name:    "explain",
+		args:    "[target...]",
+		summary: "Describe what targets do and where they come from",
+		flags:   outputFlags(false),
+		run: func(a *app.App, _ *options, args []string, stdout io.Writer) error {
+			return a.Explain(stdout, args)
+		},
+	},
+	{
+		name:    "restore",
+		args:    "[backup]",
+		summary: "List backups of overwritten files, or roll one back",
+		run: func(a *app.App, _ *options, args []string, stdout io.Writer) error {
+			switch len(args) {
+			case 0:
+				return a.Restore(stdout, "")
+			case 1:
+				return a.Restore(stdout, args[0])
+			}
+			return fmt.Errorf("restore takes a single backup")
+		},
+	},
+}
+
+// findCommand looks a subcommand up by name
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// flagSet builds the flags of a command, including the common ones
+func (c command) flagSet(opts *options, output io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.StringVar(&opts.dir, "dir", ".", "Project directory")
+	fs.BoolVar(&opts.verbose, "verbose", false, "Enable verbose output")
+	if c.flags != nil {
+		c.flags(fs, opts)
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(output, "Usage:\n  %s\n\n%s\n\nFlags:\n", strings.TrimSpace("makegen "+c.name+" [flags] "+c.args), c.summary)
+		fs.PrintDefaults()
+	}
+	return fs
 }
@@ -1,68 +1,137 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/gaoubak/Makegen/internal/app"
 	"github.com/gaoubak/Makegen/internal/utils"
 )
 
-var (
-	version  = "1.0.0"
-	verbose  = flag.Bool("verbose", false, "Enable verbose logging")
-	version_ = flag.Bool("version", false, "Show version")
-	help     = flag.Bool("help", false, "Show help")
+var version = "1.0.0"
+
+// Exit codes shared by every command
+const (
+	exitOK    = 0
+	exitError = 1 // the command failed
+	exitUsage = 2 // unknown command or invalid flags
+	exitDrift = 3 // check found the Makefile out of date
 )
 
 func main() {
-	flag.Parse()
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run dispatches to a subcommand and returns the exit code. Without a
+// subcommand makegen runs init.
+func run(args []string, stdout, stderr io.Writer) int {
+	name := "init"
+	if len(args) > 0 {
+		switch args[0] {
+		case "version", "-version", "--version":
+			fmt.Fprintf(stdout, "makegen version %s\n", version)
+			return exitOK
+		case "help", "-help", "--help", "-h":
+			return showHelp(args[1:], stdout, stderr)
+		}
+		if !strings.HasPrefix(args[0], "-") {
+			name, args = args[0], args[1:]
+		}
+	}
+
+	cmd, ok := findCommand(name)
+	if !ok {
+		fmt.Fprintf(stderr, "makegen: unknown command %q\nRun 'makegen help' for usage.\n", name)
+		return exitUsage
+	}
 
-	if *version_ {
-		fmt.Printf("makegen version %s\n", version)
-		os.Exit(0)
+	opts := &options{}
+	fs := cmd.flagSet(opts, stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if cmd.args == "" && fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "makegen %s: unexpected arguments: %s\n", cmd.name, strings.Join(fs.Args(), " "))
+		return exitUsage
 	}
 
-	if *help {
-		showHelp()
-		os.Exit(0)
+	if err := validateOptions(opts); err != nil {
+		fmt.Fprintf(stderr, "makegen %s: %v\n", cmd.name, err)
+		return exitUsage
 	}
 
-	// Initialize logger
-	logger := utils.NewLogger(*verbose)
+	logger := utils.NewLogger(opts.verbose)
 
-	// Get working directory
-	workDir, err := os.Getwd()
+	dir, err := filepath.Abs(opts.dir)
+	if err == nil {
+		var info os.FileInfo
+		if info, err = os.Stat(dir); err == nil && !info.IsDir() {
+			err = fmt.Errorf("%s is not a directory", dir)
+		}
+	}
 	if err != nil {
-		log.Fatal("Failed to get working directory:", err)
+		logger.Error("Invalid --dir: %v", err)
+		return exitError
 	}
 
-	// Create and run application
-	application := app.NewApp(logger, workDir)
-	if err := application.Run(); err != nil {
-		logger.Error("Application error: %v", err)
-		os.Exit(1)
+	application := app.NewApp(logger, dir)
+	application.SetOutput(opts.output, opts.dryRun)
+	err = cmd.run(application, opts, fs.Args(), stdout)
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, app.ErrDrift):
+		return exitDrift
+	default:
+		logger.Error("%s: %v", cmd.name, err)
+		return exitError
 	}
 }
 
-func showHelp() {
-	fmt.Println(`🔨 Makefile Generator - Interactive Makefile Creation
+// showHelp prints the command list, or the usage of one command
+func showHelp(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		cmd, ok := findCommand(args[0])
+		if !ok {
+			fmt.Fprintf(stderr, "makegen: unknown command %q\n", args[0])
+			return exitUsage
+		}
+		cmd.flagSet(&options{}, stdout).Usage()
+		return exitOK
+	}
 
-Usage:
-  makegen [flags]
+	fmt.Fprintf(stdout, "🔨 Makefile Generator - Interactive Makefile Creation\n\n")
+	fmt.Fprintf(stdout, "Usage:\n  makegen [command] [flags]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(stdout, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(stdout, "  %-10s %s\n", "version", "Show version")
+	fmt.Fprintf(stdout, "  %-10s %s\n", "help", "Show help for a command")
 
-Flags:
-  -verbose    Enable verbose output
-  -version    Show version
-  -help       Show this help message
+	fmt.Fprintf(stdout, "\nCommon flags:\n")
+	fmt.Fprintf(stdout, "  -dir string   Project directory (default \".\")\n")
+	fmt.Fprintf(stdout, "  -verbose      Enable verbose output\n")
 
-Examples:
-  makegen                  Run interactive generator
-  makegen -verbose         Run with debug output
-  makegen -version         Show version
+	fmt.Fprintf(stdout, "\nExit codes:\n")
+	fmt.Fprintf(stdout, "  %d  success\n  %d  command failed\n  %d  usage error\n  %d  check found the Makefile out of date\n",
+		exitOK, exitError, exitUsage, exitDrift)
 
-For more information, visit: https://github.com/yourusername/makegen
-`)
+	fmt.Fprintf(stdout, "\nExamples:\n")
+	fmt.Fprintf(stdout, "  makegen                      Run interactive generator\n")
+	fmt.Fprintf(stdout, "  makegen detect --format json Print the detection for tooling\n")
+	fmt.Fprintf(stdout, "  makegen generate --dir api   Generate a Makefile for ./api\n")
+	fmt.Fprintf(stdout, "  makegen update --dry-run     Show what an update would change\n")
+	fmt.Fprintf(stdout, "  makegen generate --output makegen.mk\n")
+	fmt.Fprintf(stdout, "                               Write a fragment to include from your Makefile\n")
+	fmt.Fprintf(stdout, "  makegen check                Fail CI when the Makefile drifted\n")
+	fmt.Fprintf(stdout, "  makegen explain test build   Show what targets run\n")
+	return exitOK
 }
@@ -1,7 +1,330 @@
 package app
 
-import "testing"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
 
-func TestApp(t *testing.T) {
-	t.Skip("Implementation pending")
+	"github.com/gaoubak/Makegen/internal/config"
+	"github.com/gaoubak/Makegen/internal/utils"
+)
+
+func goProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":  "module example.com/demo\n\ngo 1.23\n",
+		"main.go": "package main\n\nfunc main() {}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestGenerateCheckUpdate(t *testing.T) {
+	dir := goProject(t)
+	a := NewApp(utils.NewLogger(false), dir)
+	var out bytes.Buffer
+
+	if err := a.Check(&out, ""); !errors.Is(err, ErrDrift) {
+		t.Fatalf("Check() without Makefile error = %v, want ErrDrift", err)
+	}
+	if err := a.Generate("", false); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := a.Generate("", false); !errors.Is(err, ErrMakefileExists) {
+		t.Fatalf("Generate() over existing Makefile error = %v, want ErrMakefileExists", err)
+	}
+
+	makefile, err := os.ReadFile(filepath.Join(dir, "Makefile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "PROJECT_NAME := " + filepath.Base(dir) + "\n"; !strings.Contains(string(makefile), want) {
+		t.Errorf("Makefile missing %q", want)
+	}
+	if err := a.Check(&out, ""); err != nil {
+		t.Fatalf("Check() after Generate error = %v", err)
+	}
+
+	// Hand-written targets are kept; edits to generated ones are drift
+	edited := strings.Replace(string(makefile), "$(GO) build", "$(GO) build -v", 1) + "\nhello:\n\techo hi\n.PHONY: hello\n"
+	if err := os.WriteFile(filepath.Join(dir, "Makefile"), []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := a.Check(&out, ""); !errors.Is(err, ErrDrift) {
+		t.Fatalf("Check() after edit error = %v, want ErrDrift", err)
+	}
+	if !strings.Contains(out.String(), "~ build (changed)") || strings.Contains(out.String(), "hello") {
+		t.Errorf("Check() output = %q", out.String())
+	}
+
+	if err := a.Update(""); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := a.Check(&out, ""); err != nil {
+		t.Fatalf("Check() after Update error = %v", err)
+	}
+
+	// A target added by hand anywhere, without the preserved section
+	// header, is kept by update and so is not drift
+	withHello := "hello:\n\techo hi\n.PHONY: hello\n\n" + string(makefile)
+	if err := os.WriteFile(filepath.Join(dir, "Makefile"), []byte(withHello), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := a.Check(&out, ""); err != nil {
+		t.Fatalf("Check() with a hand-written target error = %v\n%s", err, out.String())
+	}
+
+	// Changed variables are named rather than reported as comments
+	if err := os.WriteFile(filepath.Join(dir, "Makefile"), []byte(strings.Replace(withHello, "GOFLAGS := -v", "GOFLAGS := -race", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := a.Check(&out, ""); !errors.Is(err, ErrDrift) || !strings.Contains(out.String(), "~ variable GOFLAGS (changed)") {
+		t.Fatalf("Check() with a changed variable = %v\n%s", err, out.String())
+	}
+	if err := a.Update(""); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	out.Reset()
+	if err := a.Explain(&out, []string{"build", "hello"}); err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	for _, want := range []string{"build (phony, generated)", "hello (phony, hand-written)\n  $ echo hi\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Explain() output missing %q\n%s", want, out.String())
+		}
+	}
+	if err := a.Explain(&out, []string{"missing"}); err == nil {
+		t.Error("Explain() of an unknown target should fail")
+	}
+}
+
+func TestUpdateDropsTargetsNoLongerGenerated(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":             "module example.com/demo\n\ngo 1.23\n",
+		"cmd/api/main.go":    "package main\n\nfunc main() {}\n",
+		"cmd/worker/main.go": "package main\n\nfunc main() {}\n",
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a := NewApp(utils.NewLogger(false), dir)
+	if err := a.Generate("", false); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := os.RemoveAll(filepath.Join(dir, "cmd", "worker")); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := a.Check(&out, ""); !errors.Is(err, ErrDrift) || !strings.Contains(out.String(), "- build-worker (no longer generated)\n") {
+		t.Fatalf("Check() after removing a binary = %v\n%s", err, out.String())
+	}
+	out.Reset()
+	if err := a.Explain(&out, []string{"run-worker"}); err != nil || !strings.Contains(out.String(), "run-worker (phony, no longer generated)") {
+		t.Errorf("Explain() = %v\n%s", err, out.String())
+	}
+
+	if err := a.Update(""); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if makefile, _ := os.ReadFile(filepath.Join(dir, "Makefile")); strings.Contains(string(makefile), "worker") {
+		t.Errorf("Update() kept the targets of the removed binary:\n%s", makefile)
+	}
+	if err := a.Check(&out, ""); err != nil {
+		t.Errorf("Check() after Update error = %v", err)
+	}
+}
+
+func TestUpdateKeepsSavedAnswers(t *testing.T) {
+	dir := goProject(t)
+	a := NewApp(utils.NewLogger(false), dir)
+	if err := a.Generate("", false); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	// Answers given to init that the defaults would not give
+	state := a.loadState()
+	if state == nil {
+		t.Fatal("Generate() saved no state")
+	}
+	state.Config.CustomTargets["seed"] = config.Target{Name: "seed", Commands: []string{"echo seed"}, Phony: true}
+	if err := a.storage.SaveState(dir, a.outputPath(), state); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := a.Check(&out, ""); !errors.Is(err, ErrDrift) || !strings.Contains(out.String(), "+ seed (missing)\n") {
+		t.Fatalf("Check() against saved answers = %v\n%s", err, out.String())
+	}
+	if err := a.Update(""); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	out.Reset()
+	if err := a.Explain(&out, []string{"seed"}); err != nil || !strings.Contains(out.String(), "seed (phony, generated)\n  $ echo seed\n") {
+		t.Errorf("Explain() = %v\n%s", err, out.String())
+	}
+}
+
+func TestDetectFormats(t *testing.T) {
+	dir := goProject(t)
+	if err := os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM golang\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	a := NewApp(utils.NewLogger(false), dir)
+
+	var out bytes.Buffer
+	if err := a.Detect(&out, "json"); err != nil {
+		t.Fatalf("Detect(json) error = %v", err)
+	}
+	var report DetectionReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("Detect(json) output is not JSON: %v\n%s", err, out.String())
+	}
+	if report.SchemaVersion != DetectSchemaVersion || report.Language.Name != "go" ||
+		!reflect.DeepEqual(report.Docker.Evidence, []string{"Dockerfile"}) || report.Frameworks == nil {
+		t.Errorf("Detect(json) report = %+v", report)
+	}
+
+	out.Reset()
+	if err := a.Detect(&out, "yaml"); err != nil {
+		t.Fatalf("Detect(yaml) error = %v", err)
+	}
+	for _, want := range []string{
+		"schema_version: 1\n",
+		"language:\n  name: go\n  evidence:\n    - go.mod\n",
+		"frameworks: []\n",
+		"  e2e_framework: \"\"\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Detect(yaml) missing %q\n%s", want, out.String())
+		}
+	}
+
+	if err := a.Detect(&out, "xml"); err == nil {
+		t.Error("Detect(xml) should fail")
+	}
+}
+
+func TestWriteYAML(t *testing.T) {
+	value := map[string]interface{}{
+		"items": []map[string]interface{}{{"name": "gin", "port": 8080, "tags": []string{"web"}}},
+		"empty": map[string]string{},
+		"text":  "yes",
+		"path":  "cmd/api: main",
+	}
+
+	var out bytes.Buffer
+	if err := writeYAML(&out, value); err != nil {
+		t.Fatal(err)
+	}
+	want := "empty: {}\n" +
+		"items:\n  - name: gin\n    port: 8080\n    tags:\n      - web\n" +
+		"path: \"cmd/api: main\"\n" +
+		"text: \"yes\"\n"
+	if out.String() != want {
+		t.Errorf("writeYAML() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestOutputFragmentAndDryRun(t *testing.T) {
+	dir := goProject(t)
+	a := NewApp(utils.NewLogger(false), dir)
+	var out bytes.Buffer
+	a.out = &out
+
+	a.SetOutput("makegen.mk", true)
+	if err := a.Generate("demo", false); err != nil {
+		t.Fatalf("Generate() dry run error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "makegen.mk")); !os.IsNotExist(err) {
+		t.Fatalf("dry run wrote makegen.mk: %v", err)
+	}
+	for _, want := range []string{"--- a/makegen.mk\n+++ b/makegen.mk\n@@ -0,0 ", "+# Use it from your Makefile with: include makegen.mk\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("dry run output missing %q\n%s", want, out.String())
+		}
+	}
+
+	a.SetOutput("makegen.mk", false)
+	if err := a.Generate("demo", false); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Makefile")); !os.IsNotExist(err) {
+		t.Errorf("Generate() with --output wrote a Makefile: %v", err)
+	}
+
+	out.Reset()
+	a.SetOutput("makegen.mk", true)
+	if err := a.Update(""); err != nil {
+		t.Fatalf("Update() dry run error = %v", err)
+	}
+	if out.String() != "No changes to makegen.mk\n" {
+		t.Errorf("Update() dry run output = %q", out.String())
+	}
+
+	out.Reset()
+	a.SetOutput("-", false)
+	if err := a.Generate("demo", false); err != nil {
+		t.Fatalf("Generate() to stdout error = %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "# Generated Makefile\n") {
+		t.Errorf("Generate() to stdout = %q", out.String())
+	}
+}
+
+func TestReviewAppliesAcceptedHunks(t *testing.T) {
+	dir := goProject(t)
+	a := NewApp(utils.NewLogger(false), dir)
+	var out bytes.Buffer
+	a.SetIO(strings.NewReader("n\ny\n"), &out)
+
+	current := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	if err := os.WriteFile(filepath.Join(dir, "Makefile"), []byte(current), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.review(config.NewMakefileConfig(), "Makefile", current, "", "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n"); err != nil {
+		t.Fatalf("review() error = %v", err)
+	}
+
+	saved, _ := os.ReadFile(filepath.Join(dir, "Makefile"))
+	if want := "1\n2\n3\n4\n5\n6\n7\n8\n9\nten\n"; string(saved) != want {
+		t.Errorf("review() saved %q, want %q", saved, want)
+	}
+	if strings.Contains(out.String(), "\033[") {
+		t.Error("review() colored output that is not a terminal")
+	}
+}
+
+func TestProjectNameFrom(t *testing.T) {
+	for dir, want := range map[string]string{
+		"api":          "api",
+		"My App (v2)":  "My-App-v2",
+		"_tools":       "tools",
+		"café-service": "caf-service",
+		"###":          "myproject",
+	} {
+		if got := projectNameFrom(dir); got != want {
+			t.Errorf("projectNameFrom(%q) = %q, want %q", dir, got, want)
+		}
+	}
 }
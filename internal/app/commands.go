@@ -0,0 +1,423 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gaoubak/Makegen/internal/config"
+	"github.com/gaoubak/Makegen/internal/generator"
+	"github.com/gaoubak/Makegen/internal/storage"
+	"github.com/gaoubak/Makegen/internal/ui"
+	"github.com/gaoubak/Makegen/internal/utils"
+)
+
+// ErrDrift is returned by Check when the Makefile differs from the one
+// makegen would write
+var ErrDrift = errors.New("Makefile is out of date")
+
+// ErrMakefileExists is returned by Generate when a Makefile is already
+// present and overwriting was not requested
+var ErrMakefileExists = errors.New("Makefile already exists")
+
+// Detect prints what the analyzer finds in the project, as text or as a
+// DetectionReport in "json" or "yaml"
+func (a *App) Detect(w io.Writer, format string) error {
+	detection, err := a.detector.Analyze(a.workDir)
+	if err != nil {
+		return fmt.Errorf("detection failed: %w", err)
+	}
+
+	switch format {
+	case "", "text":
+		writeDetection(w, detection)
+		return nil
+	case "json":
+		return writeJSON(w, NewDetectionReport(detection))
+	case "yaml", "yml":
+		return writeYAML(w, NewDetectionReport(detection))
+	}
+	return fmt.Errorf("unknown format %q: use text, json or yaml", format)
+}
+
+// Generate writes a Makefile built from the detected defaults without
+// asking anything. An existing file is only replaced when force is set.
+func (a *App) Generate(projectName string, force bool) error {
+	if a.hasMakefile() && !force && !a.dryRun && a.output != "-" {
+		return fmt.Errorf("%w: use --force to overwrite or 'makegen update' to merge", ErrMakefileExists)
+	}
+
+	cfg, makefile, err := a.render(projectName, nil)
+	if err != nil {
+		return err
+	}
+
+	if wrote, err := a.save(makefile); err != nil || !wrote {
+		return err
+	}
+	a.remember(cfg, makefile, makefile)
+	a.logger.Success("✅ %s generated", filepath.Base(a.outputPath()))
+	return nil
+}
+
+// Update regenerates the Makefile from the answers it was last written with,
+// or the detected defaults, keeping the hand-written targets of the existing
+// one
+func (a *App) Update(projectName string) error {
+	cfg, generated, err := a.render(projectName, a.loadState())
+	if err != nil {
+		return err
+	}
+	makefile := a.expected(generated)
+
+	name := filepath.Base(a.outputPath())
+	if current, err := a.readExisting(); err == nil && current == makefile && !a.dryRun && a.output != "-" {
+		a.remember(cfg, generated, makefile)
+		a.logger.Info("%s is already up to date", name)
+		return nil
+	}
+
+	if wrote, err := a.save(makefile); err != nil || !wrote {
+		return err
+	}
+	a.remember(cfg, generated, makefile)
+	a.logger.Success("✅ %s updated", name)
+	return nil
+}
+
+// Check compares the Makefile with what Update would write, prints the
+// generated rules, variables and directives that are missing or differ and
+// the targets and variables no longer generated, and returns ErrDrift when
+// there are any. Hand-written blocks are kept by Update, so they are not
+// drift wherever they are in the file, and neither are comments or the
+// order of blocks.
+func (a *App) Check(w io.Writer, projectName string) error {
+	name := filepath.Base(a.outputPath())
+	if !a.hasMakefile() {
+		fmt.Fprintf(w, "No %s in %s\n", name, a.workDir)
+		return ErrDrift
+	}
+
+	current, err := a.readExisting()
+	if err != nil {
+		return err
+	}
+	state := a.loadState()
+	_, generated, err := a.render(projectName, state)
+	if err != nil {
+		return err
+	}
+
+	have := storage.ParseMakefile(current)
+	want := storage.ParseMakefile(generated)
+	haveBlocks := blocksByKey(have)
+	wantBlocks := blocksByKey(want)
+
+	changes := 0
+	for _, key := range sortedKeys(wantBlocks) {
+		texts, ok := haveBlocks[key]
+		switch {
+		case !ok:
+			fmt.Fprintf(w, "+ %s (missing)\n", key)
+		case !slices.Equal(texts, wantBlocks[key]):
+			fmt.Fprintf(w, "~ %s (changed)\n", key)
+		default:
+			continue
+		}
+		changes++
+	}
+	for _, target := range want.Targets() {
+		if want.Phony[target] && !have.Phony[target] {
+			fmt.Fprintf(w, "~ %s (no longer .PHONY)\n", target)
+			changes++
+		}
+	}
+	if state != nil {
+		for _, key := range stale(have, want, state.Owned) {
+			fmt.Fprintf(w, "- %s (no longer generated)\n", key)
+			changes++
+		}
+	}
+
+	if changes == 0 {
+		fmt.Fprintf(w, "%s is up to date\n", name)
+		return nil
+	}
+	fmt.Fprintf(w, "Run 'makegen update' to bring %s up to date\n", name)
+	return ErrDrift
+}
+
+// Explain prints the prerequisites and recipe of each target, and whether
+// makegen generates it, generated it before or it was written by hand. With
+// no targets every target of the Makefile is explained.
+func (a *App) Explain(w io.Writer, targets []string) error {
+	state := a.loadState()
+	_, generated, err := a.render("", state)
+	if err != nil {
+		return err
+	}
+
+	source := generated
+	if current, err := a.readExisting(); err == nil {
+		source = current
+	}
+
+	parsed := storage.ParseMakefile(source)
+	rules := rulesByTarget(parsed)
+	owned := rulesByTarget(storage.ParseMakefile(generated))
+
+	if len(targets) == 0 {
+		targets = parsed.Targets()
+	}
+
+	var unknown []string
+	seen := make(map[string]bool)
+	for _, target := range targets {
+		if seen[target] {
+			continue
+		}
+		seen[target] = true
+
+		blocks, ok := rules[target]
+		if !ok {
+			unknown = append(unknown, target)
+			continue
+		}
+
+		origin := "hand-written"
+		if _, ok := owned[target]; ok {
+			origin = "generated"
+		} else if state != nil && slices.Contains(state.Targets, target) {
+			origin = "no longer generated"
+		}
+		kind := "file"
+		if parsed.Phony[target] {
+			kind = "phony"
+		}
+		fmt.Fprintf(w, "%s (%s, %s)\n", target, kind, origin)
+
+		var prereqs []string
+		var recipe []string
+		for _, block := range blocks {
+			prereqs = append(prereqs, block.Prereqs...)
+			if _, body, ok := strings.Cut(block.Text, "\n"); ok {
+				for _, line := range strings.Split(body, "\n") {
+					if strings.HasPrefix(line, "\t") {
+						recipe = append(recipe, strings.TrimPrefix(line, "\t"))
+					}
+				}
+			}
+		}
+		if len(prereqs) > 0 {
+			fmt.Fprintf(w, "  depends on: %s\n", strings.Join(prereqs, " "))
+		}
+		for _, line := range recipe {
+			fmt.Fprintf(w, "  $ %s\n", line)
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown target: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// Restore lists the backups kept when files are overwritten or, given a
+// backup number from that list or its file name, writes it back
+func (a *App) Restore(w io.Writer, choice string) error {
+	backups, err := a.storage.ListBackups(a.workDir)
+	if err != nil {
+		return err
+	}
+
+	if choice == "" {
+		if len(backups) == 0 {
+			fmt.Fprintf(w, "No backups in %s\n", filepath.Join(a.workDir, storage.BackupDir))
+			return nil
+		}
+		for i, backup := range backups {
+			fmt.Fprintf(w, "%3d  %s  %-12s %s\n", i+1, backup.Time.Format("2006-01-02 15:04:05"), backup.Target, filepath.Base(backup.Path))
+		}
+		fmt.Fprintf(w, "Run 'makegen restore <number>' to roll back\n")
+		return nil
+	}
+
+	for i, backup := range backups {
+		if choice != strconv.Itoa(i+1) && choice != filepath.Base(backup.Path) {
+			continue
+		}
+		if err := a.storage.RestoreBackup(a.workDir, backup); err != nil {
+			return err
+		}
+		a.logger.Success("✅ %s restored from %s", backup.Target, backup.Time.Format("2006-01-02 15:04:05"))
+		return nil
+	}
+	return fmt.Errorf("no backup %q: run 'makegen restore' to list them", choice)
+}
+
+// render builds the Makefile from the answers saved in state, with the
+// detected toolchain refreshed, or from the questionnaire's defaults when
+// state is nil. It returns the answers too.
+func (a *App) render(projectName string, state *State) (*config.MakefileConfig, string, error) {
+	detection, err := a.detector.Analyze(a.workDir)
+	if err != nil {
+		return nil, "", fmt.Errorf("detection failed: %w", err)
+	}
+
+	if projectName == "" && state != nil {
+		projectName = state.Config.ProjectName
+	}
+	name := a.projectName(projectName)
+	if err := utils.ValidateProjectName(name); err != nil {
+		return nil, "", fmt.Errorf("invalid project name %q: %w; use --name", name, err)
+	}
+
+	questionnaire := ui.NewQuestionnaire(a.logger, detection)
+	var cfg *config.MakefileConfig
+	if state != nil {
+		cfg = questionnaire.Refresh(state.Config, name)
+	} else if cfg, err = questionnaire.Defaults(name); err != nil {
+		return nil, "", fmt.Errorf("questionnaire failed: %w", err)
+	}
+
+	makefile, err := a.build(cfg)
+	return cfg, makefile, err
+}
+
+// expected is the Makefile Update writes: the generated one plus the
+// targets preserved from the existing file
+func (a *App) expected(generated string) string {
+	if a.hasMakefile() {
+		return a.mergeExisting(generated)
+	}
+	return generated
+}
+
+// projectName falls back to the PROJECT_NAME of the existing output file,
+// then to the directory name made safe for ValidateProjectName
+func (a *App) projectName(name string) string {
+	if name != "" {
+		return name
+	}
+	if current, err := a.readExisting(); err == nil {
+		for _, block := range storage.ParseMakefile(current).Blocks {
+			if block.Kind != storage.BlockVariable || block.Names[0] != "PROJECT_NAME" {
+				continue
+			}
+			line := block.Text[strings.LastIndex(block.Text, "\n")+1:]
+			if _, value, ok := strings.Cut(line, "="); ok && strings.TrimSpace(value) != "" {
+				return strings.TrimSpace(value)
+			}
+		}
+	}
+	return projectNameFrom(filepath.Base(a.workDir))
+}
+
+// projectNameFrom replaces the characters a project name cannot hold with
+// '-', e.g. "My App (v2)" becomes "My-App-v2"
+func projectNameFrom(dir string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range dir {
+		if r < 128 && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	if name := strings.Trim(b.String(), "._-"); utils.ValidateProjectName(name) == nil {
+		return name
+	}
+	return "myproject"
+}
+
+// stale returns the targets and variables of the current Makefile have that
+// an earlier run generated, as named in owned, and want no longer defines
+func stale(have, want *storage.ParsedMakefile, owned generator.Owned) []string {
+	var keys []string
+	for _, target := range kept(owned.Targets, have.Targets()) {
+		if !slices.Contains(want.Targets(), target) {
+			keys = append(keys, target)
+		}
+	}
+	for _, name := range kept(owned.Variables, have.Variables()) {
+		if !slices.Contains(want.Variables(), name) {
+			keys = append(keys, "variable "+name)
+		}
+	}
+	return keys
+}
+
+// rulesByTarget groups the rules of a Makefile by target; a target may
+// appear in several rules, e.g. when guards add prerequisites
+func rulesByTarget(parsed *storage.ParsedMakefile) map[string][]storage.Block {
+	rules := make(map[string][]storage.Block)
+	for _, block := range parsed.Blocks {
+		if block.Kind != storage.BlockRule {
+			continue
+		}
+		for _, name := range block.Names {
+			if !strings.HasPrefix(name, ".") {
+				rules[name] = append(rules[name], block)
+			}
+		}
+	}
+	return rules
+}
+
+// blocksByKey indexes the rules, variables and directives of a Makefile
+// for Check: rules by their targets, variables by name and directives by
+// their first line. Each key maps to the sorted texts of its blocks with
+// comments left out, since a target can appear in several rules.
+func blocksByKey(parsed *storage.ParsedMakefile) map[string][]string {
+	blocks := make(map[string][]string)
+	for _, block := range parsed.Blocks {
+		var lines []string
+		for _, line := range strings.Split(block.Text, "\n") {
+			if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+				lines = append(lines, strings.TrimRight(line, " \t"))
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+
+		var key string
+		switch {
+		case block.Kind == storage.BlockRule && block.Names[0] == ".PHONY":
+			continue // compared through ParsedMakefile.Phony
+		case block.Kind == storage.BlockRule:
+			key = strings.Join(block.Names, " ")
+		case block.Kind == storage.BlockVariable:
+			key = "variable " + block.Names[0]
+		case block.Kind == storage.BlockDirective:
+			key = lines[0]
+		default:
+			continue
+		}
+		blocks[key] = append(blocks[key], strings.Join(lines, "\n"))
+	}
+
+	for _, texts := range blocks {
+		sort.Strings(texts)
+	}
+	return blocks
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
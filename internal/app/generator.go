@@ -2,7 +2,12 @@ package app
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/gaoubak/Makegen/internal/config"
 	"github.com/gaoubak/Makegen/internal/detector"
 	"github.com/gaoubak/Makegen/internal/generator"
 	"github.com/gaoubak/Makegen/internal/storage"
@@ -17,6 +22,10 @@ type App struct {
 	detector  *detector.Analyzer
 	storage   storage.FileSystem
 	generator *generator.Builder
+	prompt    ui.Prompter
+	out       io.Writer
+	output    string // see SetOutput
+	dryRun    bool
 }
 
 // NewApp creates a new application instance
@@ -27,9 +36,27 @@ func NewApp(logger *utils.Logger, workDir string) *App {
 		detector:  detector.NewAnalyzer(logger),
 		storage:   storage.NewLocalFileSystem(logger),
 		generator: generator.NewBuilder(logger),
+		prompt:    ui.NewPrompter(os.Stdin, os.Stdout),
+		out:       os.Stdout,
 	}
 }
 
+// SetIO makes the interactive commands read answers from in and print
+// prompts, previews and diffs to out
+func (a *App) SetIO(in io.Reader, out io.Writer) {
+	a.prompt = ui.NewPrompter(in, out)
+	a.out = out
+}
+
+// SetOutput makes the commands write path instead of the Makefile: a file
+// relative to the project directory such as GNUmakefile or an include
+// fragment like makegen.mk, or "-" for stdout. With dryRun they print a
+// diff against the existing file and write nothing.
+func (a *App) SetOutput(path string, dryRun bool) {
+	a.output = path
+	a.dryRun = dryRun
+}
+
 // Run executes the main application flow
 func (a *App) Run() error {
 	a.logger.Info("🔨 Makefile Generator - Interactive Setup")
@@ -48,7 +75,7 @@ func (a *App) Run() error {
 	a.logger.Info("\n❓ Configuration Questions")
 	a.logger.Info("=======================\n")
 
-	questionnaire := ui.NewQuestionnaire(a.logger, detection)
+	questionnaire := ui.NewQuestionnaireWithPrompter(a.logger, detection, a.prompt, a.out)
 	config, err := questionnaire.Ask()
 	if err != nil {
 		return fmt.Errorf("questionnaire failed: %w", err)
@@ -56,31 +83,155 @@ func (a *App) Run() error {
 
 	// Phase 3: Generate Makefile
 	a.logger.Info("\n📝 Generating Makefile...")
-	makefile, err := a.generator.Build(config)
+	generated, err := a.build(config)
 	if err != nil {
-		return fmt.Errorf("generation failed: %w", err)
+		return err
+	}
+
+	makefile := generated
+	if a.hasMakefile() {
+		makefile = a.mergeExisting(generated)
+	}
+
+	// Dry runs and stdout output need no confirmation
+	if a.dryRun || a.output == "-" {
+		_, err := a.save(makefile)
+		return err
+	}
+
+	name := filepath.Base(a.outputPath())
+
+	// Phase 4: Review changes to an existing file hunk by hunk
+	if current, err := a.readExisting(); err == nil {
+		return a.review(config, name, current, generated, makefile)
 	}
 
 	// Phase 4: Preview and Save
 	a.logger.Info("\n✨ Preview:")
 	a.logger.Info("===========\n")
-	fmt.Println(makefile)
+	fmt.Fprintln(a.out, makefile)
 	a.logger.Info("\n===========\n")
 
 	// Phase 5: Save to File
-	shouldSave := ui.PromptYesNo("Save to Makefile?", true)
+	shouldSave := a.prompt.Confirm(fmt.Sprintf("Save to %s?", name), true)
 	if shouldSave {
-		if err := a.storage.WriteMakefile(a.workDir, makefile); err != nil {
-			return fmt.Errorf("failed to save Makefile: %w", err)
+		if _, err := a.save(makefile); err != nil {
+			return err
 		}
-		a.logger.Success("✅ Makefile saved successfully!")
+		a.remember(config, generated, makefile)
+		a.logger.Success("✅ %s saved successfully!", name)
 	} else {
-		a.logger.Info("❌ Makefile not saved")
+		a.logger.Info("❌ %s not saved", name)
 	}
 
 	return nil
 }
 
+// review shows the diff between the current content and makefile, the
+// content cfg generated merged with it, and saves only the hunks the user
+// accepts
+func (a *App) review(cfg *config.MakefileConfig, name, current, generated, makefile string) error {
+	hunks := utils.Diff(current, makefile, 3)
+	if len(hunks) == 0 {
+		a.logger.Info("%s is already up to date", name)
+		return nil
+	}
+
+	a.logger.Info("\n✨ Changes to %s:", name)
+	color := ui.ColorEnabled(a.out)
+	accepted := ui.ReviewHunks(a.prompt, a.out, name, hunks, color)
+
+	updated := utils.ApplyHunks(current, hunks, accepted)
+	if updated == current {
+		a.logger.Info("❌ %s not changed", name)
+		return nil
+	}
+
+	if _, err := a.save(updated); err != nil {
+		return err
+	}
+	a.remember(cfg, generated, updated)
+	applied := 0
+	for _, ok := range accepted {
+		if ok {
+			applied++
+		}
+	}
+	a.logger.Success("✅ Applied %d of %d changes to %s", applied, len(hunks), name)
+	return nil
+}
+
+// outputPath is the file the commands read and write. Without --output it
+// is the GNUmakefile when the project only has one, since make prefers it,
+// and the Makefile otherwise. Stdout output merges with that same file.
+func (a *App) outputPath() string {
+	switch {
+	case a.output != "" && a.output != "-":
+		if filepath.IsAbs(a.output) {
+			return a.output
+		}
+		return filepath.Join(a.workDir, a.output)
+	case !a.storage.FileExists(filepath.Join(a.workDir, "Makefile")) &&
+		a.storage.FileExists(filepath.Join(a.workDir, "GNUmakefile")):
+		return filepath.Join(a.workDir, "GNUmakefile")
+	}
+	return filepath.Join(a.workDir, "Makefile")
+}
+
+// hasMakefile reports whether the output file exists
+func (a *App) hasMakefile() bool {
+	return a.storage.FileExists(a.outputPath())
+}
+
+// readExisting reads the output file
+func (a *App) readExisting() (string, error) {
+	return a.storage.ReadFile(a.outputPath())
+}
+
+// build generates the Makefile, as an include fragment when the output is
+// not a Makefile make reads by itself
+func (a *App) build(cfg *config.MakefileConfig) (string, error) {
+	cfg.Fragment = ""
+	switch name := filepath.Base(a.outputPath()); name {
+	case "Makefile", "makefile", "GNUmakefile":
+	default:
+		cfg.Fragment = name
+	}
+
+	makefile, err := a.generator.Build(cfg)
+	if err != nil {
+		return "", fmt.Errorf("generation failed: %w", err)
+	}
+	return makefile, nil
+}
+
+// save writes the content to the output, or prints it or its diff. It
+// reports whether a file was written.
+func (a *App) save(content string) (bool, error) {
+	path := a.outputPath()
+
+	switch {
+	case a.dryRun:
+		current, _ := a.readExisting()
+		name, _ := filepath.Rel(a.workDir, path)
+		hunks := utils.Diff(current, content, 3)
+		if len(hunks) == 0 {
+			fmt.Fprintf(a.out, "No changes to %s\n", name)
+		} else {
+			fmt.Fprint(a.out, ui.FormatDiff("a/"+name, "b/"+name, hunks, ui.ColorEnabled(a.out)))
+		}
+		return false, nil
+	case a.output == "-":
+		_, err := fmt.Fprint(a.out, content)
+		return false, err
+	}
+
+	if err := a.storage.WriteFile(a.workDir, path, content); err != nil {
+		return false, fmt.Errorf("failed to save %s: %w", filepath.Base(path), err)
+	}
+	return true, nil
+}
+
 // logDetectionResults logs what was detected
 func (a *App) logDetectionResults(detection *detector.Result) {
 	a.logger.Info("✓ Language: %s", detection.Language)
@@ -91,3 +242,19 @@ func (a *App) logDetectionResults(detection *detector.Result) {
 	}
 	a.logger.Info("")
 }
+
+// mergeExisting carries hand-written targets of the current Makefile over
+// into the generated one, dropping those an earlier run generated
+func (a *App) mergeExisting(makefile string) string {
+	existing, err := a.readExisting()
+	if err != nil {
+		a.logger.Warn("Could not read existing %s: %v", filepath.Base(a.outputPath()), err)
+		return makefile
+	}
+
+	merged, preserved := generator.Merge(makefile, storage.ParseMakefile(existing), a.owned())
+	if len(preserved) > 0 {
+		a.logger.Info("♻️  Keeping %d targets from existing %s: %s", len(preserved), filepath.Base(a.outputPath()), strings.Join(preserved, ", "))
+	}
+	return merged
+}
@@ -0,0 +1,184 @@
+package app
+
+import (
+	"github.com/gaoubak/Makegen/internal/detector"
+)
+
+// DetectSchemaVersion is the version of DetectionReport. It changes when a
+// field is renamed, removed or changes meaning; adding fields keeps it.
+// README.md documents the schema.
+const DetectSchemaVersion = 1
+
+// DetectionReport is what `makegen detect --format json|yaml` prints. Every
+// key is always present: lists are empty rather than omitted and strings
+// are empty when nothing was found. File paths are relative to
+// project_root.
+type DetectionReport struct {
+	SchemaVersion int               `json:"schema_version"`
+	ProjectRoot   string            `json:"project_root"`
+	Language      LanguageReport    `json:"language"`
+	Frameworks    []FrameworkReport `json:"frameworks"`
+	Build         BuildReport       `json:"build"`
+	Entrypoints   EntrypointReport  `json:"entrypoints"`
+	Testing       TestingReport     `json:"testing"`
+	Tooling       ToolingReport     `json:"tooling"`
+	Docker        DockerReport      `json:"docker"`
+	Database      DatabaseReport    `json:"database"`
+	Files         FilesReport       `json:"files"`
+}
+
+// LanguageReport is the primary language and the manifests that decided
+// it
+type LanguageReport struct {
+	Name     string   `json:"name"`
+	Evidence []string `json:"evidence"`
+}
+
+// FrameworkReport is a detected framework and the files that revealed it
+type FrameworkReport struct {
+	Name     string            `json:"name"`
+	Type     string            `json:"type"` // "web", "cli", "orm", "frontend", ...
+	Port     int               `json:"port"` // 0 when unknown
+	Evidence []string          `json:"evidence"`
+	Commands map[string]string `json:"commands"`
+}
+
+// BuildReport describes the build system
+type BuildReport struct {
+	Tool           string   `json:"tool"`            // "maven", "gradle", "cmake", ...
+	Wrapper        bool     `json:"wrapper"`         // mvnw or gradlew committed
+	ModulePath     string   `json:"module_path"`     // Go module path
+	Modules        []string `json:"modules"`         // go.work or multi-module build members
+	PackageManager string   `json:"package_manager"` // JavaScript: "npm", "pnpm" or "yarn"
+	Vendor         bool     `json:"vendor"`
+	BuildDir       bool     `json:"build_dir"`
+}
+
+// EntrypointReport lists what the project runs
+type EntrypointReport struct {
+	Main     string   `json:"main"`
+	Binaries []string `json:"binaries"` // Go main packages: "." or "cmd/<name>"
+}
+
+// TestingReport describes the test setup
+type TestingReport struct {
+	Framework        string `json:"framework"`     // "go test", "vitest", "pytest", ...
+	E2EFramework     string `json:"e2e_framework"` // "playwright" or "cypress"
+	TestDir          bool   `json:"test_dir"`
+	IntegrationTests bool   `json:"integration_tests"`
+	Benchmarks       bool   `json:"benchmarks"`
+}
+
+// ToolingReport lists linters, formatters and code generators
+type ToolingReport struct {
+	LintTools   []string          `json:"lint_tools"`
+	FormatTools []string          `json:"format_tools"`
+	Generators  []GeneratorReport `json:"generators"`
+}
+
+// GeneratorReport is a code generator and the file it reads
+type GeneratorReport struct {
+	Name   string `json:"name"`
+	Config string `json:"config"`
+}
+
+// DockerReport describes the container setup
+type DockerReport struct {
+	Detected bool     `json:"detected"`
+	Evidence []string `json:"evidence"`
+	Services []string `json:"services"`
+}
+
+// DatabaseReport describes migrations and the database service
+type DatabaseReport struct {
+	MigrationTool string `json:"migration_tool"`
+	MigrationDir  string `json:"migration_dir"`
+	Service       string `json:"service"` // compose service running the database
+}
+
+// FilesReport lists the dependency and config files found
+type FilesReport struct {
+	Dependency []string `json:"dependency"`
+	Config     []string `json:"config"`
+	Makefile   bool     `json:"makefile"`
+}
+
+// NewDetectionReport converts a detection result to the report schema
+func NewDetectionReport(detection *detector.Result) *DetectionReport {
+	report := &DetectionReport{
+		SchemaVersion: DetectSchemaVersion,
+		ProjectRoot:   detection.ProjectRoot,
+		Language: LanguageReport{
+			Name:     detection.Language,
+			Evidence: orEmpty(detection.LanguageFiles),
+		},
+		Frameworks: []FrameworkReport{},
+		Build: BuildReport{
+			Tool:           detection.BuildTool,
+			Wrapper:        detection.HasWrapper,
+			ModulePath:     detection.ModulePath,
+			Modules:        orEmpty(detection.Modules),
+			PackageManager: detection.PackageManager,
+			Vendor:         detection.HasVendor,
+			BuildDir:       detection.BuildDirFound,
+		},
+		Entrypoints: EntrypointReport{
+			Main:     detection.MainEntrypoint,
+			Binaries: orEmpty(detection.Binaries),
+		},
+		Testing: TestingReport{
+			Framework:        detection.TestFramework,
+			E2EFramework:     detection.E2EFramework,
+			TestDir:          detection.TestDirFound,
+			IntegrationTests: detection.IntegrationTests,
+			Benchmarks:       detection.HasBenchmarks,
+		},
+		Tooling: ToolingReport{
+			LintTools:   orEmpty(detection.LintTools),
+			FormatTools: orEmpty(detection.FormatTools),
+			Generators:  []GeneratorReport{},
+		},
+		Docker: DockerReport{
+			Detected: detection.DockerDetected,
+			Evidence: orEmpty(detection.DockerFiles),
+			Services: orEmpty(detection.DockerServices),
+		},
+		Database: DatabaseReport{
+			MigrationTool: detection.MigrationTool,
+			MigrationDir:  detection.MigrationDir,
+			Service:       detection.DatabaseService,
+		},
+		Files: FilesReport{
+			Dependency: orEmpty(detection.DependencyFiles),
+			Config:     orEmpty(detection.ConfigFiles),
+			Makefile:   detection.HasMakefile,
+		},
+	}
+
+	for _, framework := range detection.Frameworks {
+		commands := framework.Commands
+		if commands == nil {
+			commands = map[string]string{}
+		}
+		report.Frameworks = append(report.Frameworks, FrameworkReport{
+			Name:     framework.Name,
+			Type:     framework.Type,
+			Port:     framework.Port,
+			Evidence: orEmpty(framework.Files),
+			Commands: commands,
+		})
+	}
+	for _, gen := range detection.Generators {
+		report.Tooling.Generators = append(report.Tooling.Generators, GeneratorReport{Name: gen.Name, Config: gen.Config})
+	}
+
+	return report
+}
+
+// orEmpty turns a nil list into an empty one so it is encoded as []
+func orEmpty(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
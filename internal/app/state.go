@@ -0,0 +1,78 @@
+package app
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+
+	"github.com/gaoubak/Makegen/internal/config"
+	"github.com/gaoubak/Makegen/internal/generator"
+	"github.com/gaoubak/Makegen/internal/storage"
+)
+
+// State is what makegen remembers about a file it wrote: the answers that
+// produced it, so that update, check and explain regenerate the same file
+// rather than the defaults, and the targets and variables it generated, so
+// that update drops them once they are no longer generated instead of
+// keeping them as hand-written
+type State struct {
+	Config *config.MakefileConfig `json:"config"`
+	generator.Owned
+}
+
+// loadState returns the state saved for the output file, or nil when
+// makegen has not written it
+func (a *App) loadState() *State {
+	state := &State{Config: config.NewMakefileConfig()}
+	err := a.storage.LoadState(a.workDir, a.outputPath(), state)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err != nil:
+		a.logger.Warn("Ignoring saved state: %v", err)
+		return nil
+	}
+	return state
+}
+
+// remember saves the state of the output file once written holds it: the
+// answers cfg that generated it. The targets and variables of generated are
+// owned, and so are those an earlier run generated that written still
+// defines, e.g. when a review kept them.
+func (a *App) remember(cfg *config.MakefileConfig, generated, written string) {
+	gen := storage.ParseMakefile(generated)
+	state := &State{Config: cfg, Owned: generator.Owned{Targets: gen.Targets(), Variables: gen.Variables()}}
+
+	if previous := a.loadState(); previous != nil {
+		file := storage.ParseMakefile(written)
+		state.Targets = append(state.Targets, kept(previous.Targets, file.Targets())...)
+		state.Variables = append(state.Variables, kept(previous.Variables, file.Variables())...)
+	}
+	slices.Sort(state.Targets)
+	state.Targets = slices.Compact(state.Targets)
+	slices.Sort(state.Variables)
+	state.Variables = slices.Compact(state.Variables)
+
+	if err := a.storage.SaveState(a.workDir, a.outputPath(), state); err != nil {
+		a.logger.Warn("Could not save state: %v", err)
+	}
+}
+
+// owned returns what an earlier run generated in the output file
+func (a *App) owned() generator.Owned {
+	if state := a.loadState(); state != nil {
+		return state.Owned
+	}
+	return generator.Owned{}
+}
+
+// kept returns the names that are still in current
+func kept(names, current []string) []string {
+	var result []string
+	for _, name := range names {
+		if slices.Contains(current, name) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
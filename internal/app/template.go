@@ -1,3 +1,192 @@
 package app
 
-// TODO: Implement output formatting
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/detector"
+)
+
+// writeDetection prints a detection result as aligned "key: value" lines,
+// skipping what was not found
+func writeDetection(w io.Writer, detection *detector.Result) {
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "%-18s %s\n", name+":", value)
+		}
+	}
+	list := func(name string, values []string) {
+		field(name, strings.Join(values, ", "))
+	}
+	flag := func(name string, value bool) {
+		if value {
+			field(name, "yes")
+		}
+	}
+
+	field("Project root", detection.ProjectRoot)
+	field("Language", detection.Language)
+
+	var frameworks []string
+	for _, framework := range detection.Frameworks {
+		frameworks = append(frameworks, framework.Name)
+	}
+	list("Frameworks", frameworks)
+
+	field("Build tool", detection.BuildTool)
+	flag("Build wrapper", detection.HasWrapper)
+	field("Module path", detection.ModulePath)
+	list("Modules", detection.Modules)
+	list("Binaries", detection.Binaries)
+	field("Entrypoint", detection.MainEntrypoint)
+	field("Package manager", detection.PackageManager)
+
+	var generators []string
+	for _, gen := range detection.Generators {
+		generators = append(generators, gen.Name)
+	}
+	list("Generators", generators)
+
+	field("Test framework", detection.TestFramework)
+	field("E2E framework", detection.E2EFramework)
+	flag("Integration tests", detection.IntegrationTests)
+	flag("Benchmarks", detection.HasBenchmarks)
+	list("Lint tools", detection.LintTools)
+	list("Format tools", detection.FormatTools)
+
+	flag("Docker", detection.DockerDetected)
+	list("Docker services", detection.DockerServices)
+	field("Migration tool", detection.MigrationTool)
+	field("Migration dir", detection.MigrationDir)
+	field("Database service", detection.DatabaseService)
+
+	list("Dependency files", detection.DependencyFiles)
+	list("Config files", detection.ConfigFiles)
+	flag("Vendor directory", detection.HasVendor)
+	flag("Makefile", detection.HasMakefile)
+}
+
+// writeJSON prints a value as indented JSON
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// yamlNode is a decoded JSON value with object keys kept in order
+type yamlNode struct {
+	keys     []string    // object keys, nil for lists and scalars
+	children []*yamlNode // object values or list items
+	scalar   string      // YAML text of a scalar
+	isList   bool
+	isObject bool
+}
+
+// plainYAMLRe matches strings that need no quotes in YAML
+var plainYAMLRe = regexp.MustCompile(`^[A-Za-z0-9_./$(][A-Za-z0-9_ ./@+$()-]*$`)
+
+// writeYAML prints a value as block-style YAML. It goes through the JSON
+// encoding so the json tags and field order define both formats.
+func writeYAML(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	root, err := decodeYAMLNode(decoder)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	emitYAML(&b, root, 0)
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+func decodeYAMLNode(decoder *json.Decoder) (*yamlNode, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		node := &yamlNode{isList: t == '[', isObject: t == '{'}
+		for decoder.More() {
+			if node.isObject {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.keys = append(node.keys, key.(string))
+			}
+			child, err := decodeYAMLNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+			node.children = append(node.children, child)
+		}
+		_, err := decoder.Token() // closing delimiter
+		return node, err
+	case string:
+		return &yamlNode{scalar: yamlString(t)}, nil
+	case json.Number:
+		return &yamlNode{scalar: t.String()}, nil
+	case bool:
+		return &yamlNode{scalar: strconv.FormatBool(t)}, nil
+	default:
+		return &yamlNode{scalar: "null"}, nil
+	}
+}
+
+// yamlString quotes strings that YAML would read as another type or
+// cannot hold unquoted
+func yamlString(s string) string {
+	switch strings.ToLower(s) {
+	case "", "true", "false", "yes", "no", "on", "off", "null", "~":
+		return strconv.Quote(s)
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil || !plainYAMLRe.MatchString(s) || strings.HasSuffix(s, " ") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// emitYAML writes the entries of an object or list at the given indent
+func emitYAML(b *strings.Builder, node *yamlNode, indent int) {
+	pad := strings.Repeat(" ", indent)
+
+	for i, child := range node.children {
+		prefix := pad + "- "
+		if node.isObject {
+			prefix = pad + yamlString(node.keys[i]) + ":"
+		}
+
+		switch {
+		case (child.isList || child.isObject) && len(child.children) == 0:
+			empty := "[]"
+			if child.isObject {
+				empty = "{}"
+			}
+			fmt.Fprintf(b, "%s %s\n", strings.TrimRight(prefix, " "), empty)
+		case child.isObject && node.isList:
+			// The first key shares the line of the dash
+			var item strings.Builder
+			emitYAML(&item, child, indent+2)
+			fmt.Fprintf(b, "%s%s", prefix, strings.TrimPrefix(item.String(), pad+"  "))
+		case child.isList || child.isObject:
+			fmt.Fprintf(b, "%s\n", strings.TrimRight(prefix, " "))
+			emitYAML(b, child, indent+2)
+		default:
+			fmt.Fprintf(b, "%s %s\n", strings.TrimRight(prefix, " "), child.scalar)
+		}
+	}
+}
@@ -2,22 +2,49 @@ package config
 
 // MakefileConfig represents the complete Makefile configuration
 type MakefileConfig struct {
-	ProjectName    string
-	Language       string
-	Framework      *FrameworkConfig
-	HasDocker      bool
-	DockerImage    string
-	DockerServices []string
-	DockerCompose  bool
-	EnableCI       bool
-	EnableDeploy   bool
-	BuildTools     []string
-	TestFramework  string
-	LintTools      []string
-	FormatTools    []string
-	CustomTargets  map[string]Target
+	ProjectName      string
+	Language         string
+	Framework        *FrameworkConfig
+	Entrypoint       string
+	HasDocker        bool
+	DockerImage      string
+	DockerServices   []string
+	DockerCompose    bool
+	EnableCI         bool
+	EnableDeploy     bool
+	EnableRelease    bool
+	Platforms        []string
+	BuildTools       []string
+	Generators       []CodeGenerator
+	MigrationTool    string
+	MigrationDir     string
+	DatabaseService  string
+	BuildTool        string
+	UseWrapper       bool
+	Modules          []string
+	BuildPresets     map[string]string // CMake configure preset name -> binary directory
+	ModulePath       string
+	Binaries         []string
+	TestFramework    string
+	E2EFramework     string
+	IntegrationTests bool
+	EnableCoverage   bool
+	EnableBench      bool
+	BenchPackages    []string // Go packages with benchmarks: "." or "./<dir>"
+	EnableSecurity   bool
+	PinTools         bool
+	EnableGuards     bool
+	PackageManager   string
+	LintTools        []string
+	FormatTools      []string
+	GolangciLintV2   bool // the golangci config declares version: "2"
+	CustomTargets    map[string]Target
+	Fragment         string // include file name, e.g. "makegen.mk", when not writing the Makefile itself
 }
 
+// DefaultPlatforms are the GOOS/GOARCH pairs of the Go release matrix
+var DefaultPlatforms = []string{"linux/amd64", "linux/arm64", "darwin/arm64", "windows/amd64"}
+
 // FrameworkConfig represents a selected framework
 type FrameworkConfig struct {
 	Name     string
@@ -26,6 +53,12 @@ type FrameworkConfig struct {
 	Port     int
 }
 
+// CodeGenerator is a code generation step run by `make generate`
+type CodeGenerator struct {
+	Name   string
+	Config string
+}
+
 // Target represents a Makefile target
 type Target struct {
 	Name         string
@@ -33,6 +66,7 @@ type Target struct {
 	Commands     []string
 	Description  string
 	Phony        bool
+	Guard        string // variable that must be set, as in `make deploy ENV=prod`
 }
 
 // Variable represents a Makefile variable
@@ -50,6 +84,9 @@ func NewMakefileConfig() *MakefileConfig {
 		LintTools:      []string{},
 		FormatTools:    []string{},
 		DockerServices: []string{},
+		Modules:        []string{},
+		Binaries:       []string{},
+		Platforms:      append([]string{}, DefaultPlatforms...),
 	}
 }
 
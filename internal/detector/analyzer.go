@@ -12,25 +12,47 @@ import (
 
 // Result contains all detection results
 type Result struct {
-	Language        string
-	Frameworks      []Framework
-	DockerDetected  bool
-	DockerServices  []string
-	TestDirFound    bool
-	BuildDirFound   bool
-	HasVendor       bool
-	HasModules      bool
-	DependencyFiles []string
-	ConfigFiles     []string
-	MainEntrypoint  string
-	ProjectRoot     string
+	Language         string
+	LanguageFiles    []string // manifests the language was detected from
+	Frameworks       []Framework
+	DockerDetected   bool
+	DockerServices   []string
+	DockerFiles      []string // Dockerfile and compose files found
+	TestDirFound     bool
+	BuildDirFound    bool
+	HasVendor        bool
+	HasModules       bool
+	HasMakefile      bool              // an existing Makefile to merge into, not a language signal
+	BuildTool        string            // "maven", "gradle", "cmake", ...
+	HasWrapper       bool              // build tool wrapper (mvnw, gradlew) committed
+	Modules          []string          // sub-modules of a multi-module build or go.work
+	BuildPresets     map[string]string // CMake configure preset name -> binary directory
+	ModulePath       string            // Go module path from go.mod
+	Binaries         []string          // Go main packages: "." or "cmd/<name>"
+	Generators       []Generator
+	MigrationTool    string // "golang-migrate", "goose", "alembic", "django", "prisma", "rails", "flyway", "liquibase"
+	MigrationDir     string
+	DatabaseService  string // compose service running the database
+	LintTools        []string
+	FormatTools      []string
+	GolangciLintV2   bool     // the golangci config declares version: "2"
+	TestFramework    string   // unit test runner: "go test", "vitest", "pytest"...
+	E2EFramework     string   // "playwright" or "cypress"
+	IntegrationTests bool     // integration tests behind a Go build tag or pytest marker
+	HasBenchmarks    bool     // Go Benchmark funcs, pytest-benchmark, Cargo benches/ or *.bench.* files
+	BenchPackages    []string // Go packages with benchmarks: "." or "./<dir>"
+	PackageManager   string   // JavaScript: "npm", "pnpm" or "yarn"
+	DependencyFiles  []string
+	ConfigFiles      []string
+	MainEntrypoint   string
+	ProjectRoot      string
 }
 
 // Framework represents a detected framework
 type Framework struct {
 	Name     string
-	Type     string // "web", "cli", "orm", "frontend", etc.
-	Files    []string
+	Type     string   // "web", "cli", "orm", "frontend", etc.
+	Files    []string // files the framework was detected in, relative to the project
 	Commands map[string]string
 	Port     int
 	DevTools []string
@@ -101,9 +123,13 @@ func (a *Analyzer) Analyze(projectPath string) (*Result, error) {
 // detectLanguage detects the primary programming language
 func (a *Analyzer) detectLanguage(path string, result *Result) error {
 	// Check for Go
-	if fileExists(filepath.Join(path, "go.mod")) {
+	if fileExists(filepath.Join(path, "go.mod")) ||
+		fileExists(filepath.Join(path, "go.work")) {
 		result.Language = "go"
+		result.LanguageFiles = existingFiles(path, "go.mod", "go.work")
 		result.HasModules = true
+		result.ModulePath = goModulePath(filepath.Join(path, "go.mod"))
+		result.Modules = a.goWorkModules(path)
 		return nil
 	}
 
@@ -112,6 +138,15 @@ func (a *Analyzer) detectLanguage(path string, result *Result) error {
 		fileExists(filepath.Join(path, "setup.py")) ||
 		fileExists(filepath.Join(path, "pyproject.toml")) {
 		result.Language = "python"
+		result.LanguageFiles = existingFiles(path, "requirements.txt", "setup.py", "pyproject.toml")
+		return nil
+	}
+
+	// Check for Deno (before Node: Deno projects may carry a package.json)
+	if fileExists(filepath.Join(path, "deno.json")) ||
+		fileExists(filepath.Join(path, "deno.jsonc")) {
+		result.Language = "deno"
+		result.LanguageFiles = existingFiles(path, "deno.json", "deno.jsonc")
 		return nil
 	}
 
@@ -123,6 +158,7 @@ func (a *Analyzer) detectLanguage(path string, result *Result) error {
 		} else {
 			result.Language = "javascript"
 		}
+		result.LanguageFiles = existingFiles(path, "package.json", "tsconfig.json")
 		result.HasModules = true
 		return nil
 	}
@@ -130,37 +166,72 @@ func (a *Analyzer) detectLanguage(path string, result *Result) error {
 	// Check for Rust
 	if fileExists(filepath.Join(path, "Cargo.toml")) {
 		result.Language = "rust"
+		result.LanguageFiles = []string{"Cargo.toml"}
 		return nil
 	}
 
-	// Check for Java
-	if fileExists(filepath.Join(path, "pom.xml")) {
-		result.Language = "java"
-		return nil
-	}
-
-	if fileExists(filepath.Join(path, "build.gradle")) ||
+	// Check for Java/Kotlin
+	if fileExists(filepath.Join(path, "pom.xml")) ||
+		fileExists(filepath.Join(path, "build.gradle")) ||
 		fileExists(filepath.Join(path, "build.gradle.kts")) {
-		result.Language = "java"
+		a.detectJVMBuild(path, result)
 		return nil
 	}
 
 	// Check for Ruby
 	if fileExists(filepath.Join(path, "Gemfile")) {
 		result.Language = "ruby"
+		result.LanguageFiles = []string{"Gemfile"}
 		return nil
 	}
 
 	// Check for PHP
 	if fileExists(filepath.Join(path, "composer.json")) {
 		result.Language = "php"
+		result.LanguageFiles = []string{"composer.json"}
+		return nil
+	}
+
+	// Check for Elixir
+	if fileExists(filepath.Join(path, "mix.exs")) {
+		result.Language = "elixir"
+		result.LanguageFiles = []string{"mix.exs"}
+		return nil
+	}
+
+	// Check for .NET
+	if a.detectDotnetProject(path, result) {
+		return nil
+	}
+
+	// Check for Dart/Flutter
+	if fileExists(filepath.Join(path, "pubspec.yaml")) {
+		result.Language = "dart"
+		result.LanguageFiles = []string{"pubspec.yaml"}
+		return nil
+	}
+
+	// Check for Zig
+	if fileExists(filepath.Join(path, "build.zig")) {
+		result.Language = "zig"
+		result.LanguageFiles = []string{"build.zig"}
+		return nil
+	}
+
+	// Check for Swift
+	if fileExists(filepath.Join(path, "Package.swift")) {
+		result.Language = "swift"
+		result.LanguageFiles = []string{"Package.swift"}
+		return nil
+	}
+
+	// Check for Haskell
+	if a.detectHaskellBuild(path, result) {
 		return nil
 	}
 
 	// Check for C/C++
-	if fileExists(filepath.Join(path, "CMakeLists.txt")) ||
-		fileExists(filepath.Join(path, "Makefile")) {
-		result.Language = "cpp"
+	if a.detectCBuild(path, result) {
 		return nil
 	}
 
@@ -186,10 +257,22 @@ func (a *Analyzer) detectFrameworks(path string, result *Result) error {
 		a.detectPythonFrameworks(path, result)
 	case "rust":
 		a.detectRustFrameworks(path, result)
-	case "java":
+	case "java", "kotlin":
 		a.detectJavaFrameworks(path, result)
 	case "ruby":
 		a.detectRubyFrameworks(path, result)
+	case "php":
+		a.detectPHPFrameworks(path, result)
+	case "elixir":
+		a.detectElixirFrameworks(path, result)
+	case "dotnet":
+		a.detectDotnetFrameworks(path, result)
+	case "dart":
+		a.detectDartFrameworks(path, result)
+	case "deno":
+		a.detectDenoFrameworks(path, result)
+	case "swift":
+		a.detectSwiftFrameworks(path, result)
 	}
 
 	return nil
@@ -208,9 +291,10 @@ func (a *Analyzer) detectGoFrameworks(path string, result *Result) {
 
 	if hasContent(content, "github.com/gin-gonic/gin") {
 		result.Frameworks = append(result.Frameworks, Framework{
-			Name: "Gin",
-			Type: "web",
-			Port: 3000,
+			Name:  "Gin",
+			Files: []string{"go.mod"},
+			Type:  "web",
+			Port:  3000,
 		})
 		a.logger.Debug("✓ Detected: Gin")
 		found = true
@@ -218,9 +302,10 @@ func (a *Analyzer) detectGoFrameworks(path string, result *Result) {
 
 	if hasContent(content, "github.com/labstack/echo") {
 		result.Frameworks = append(result.Frameworks, Framework{
-			Name: "Echo",
-			Type: "web",
-			Port: 8080,
+			Name:  "Echo",
+			Files: []string{"go.mod"},
+			Type:  "web",
+			Port:  8080,
 		})
 		a.logger.Debug("✓ Detected: Echo")
 		found = true
@@ -228,9 +313,10 @@ func (a *Analyzer) detectGoFrameworks(path string, result *Result) {
 
 	if hasContent(content, "github.com/gofiber/fiber") {
 		result.Frameworks = append(result.Frameworks, Framework{
-			Name: "Fiber",
-			Type: "web",
-			Port: 3000,
+			Name:  "Fiber",
+			Files: []string{"go.mod"},
+			Type:  "web",
+			Port:  3000,
 		})
 		a.logger.Debug("✓ Detected: Fiber")
 		found = true
@@ -238,8 +324,9 @@ func (a *Analyzer) detectGoFrameworks(path string, result *Result) {
 
 	if hasContent(content, "gorm.io/gorm") {
 		result.Frameworks = append(result.Frameworks, Framework{
-			Name: "GORM",
-			Type: "orm",
+			Name:  "GORM",
+			Files: []string{"go.mod"},
+			Type:  "orm",
 		})
 		a.logger.Debug("✓ Detected: GORM")
 		found = true
@@ -283,9 +370,10 @@ func (a *Analyzer) detectJavaScriptFrameworks(path string, result *Result) {
 	// Check for Next.js
 	if _, ok := deps["next"]; ok {
 		result.Frameworks = append(result.Frameworks, Framework{
-			Name: "Next.js",
-			Type: "web",
-			Port: 3000,
+			Name:  "Next.js",
+			Files: []string{"package.json"},
+			Type:  "web",
+			Port:  3000,
 		})
 		a.logger.Debug("✓ Detected: Next.js")
 		found = true
@@ -294,9 +382,10 @@ func (a *Analyzer) detectJavaScriptFrameworks(path string, result *Result) {
 	// Check for React
 	if _, ok := deps["react"]; ok {
 		result.Frameworks = append(result.Frameworks, Framework{
-			Name: "React",
-			Type: "frontend",
-			Port: 3000,
+			Name:  "React",
+			Files: []string{"package.json"},
+			Type:  "frontend",
+			Port:  3000,
 		})
 		a.logger.Debug("✓ Detected: React")
 		found = true
@@ -305,9 +394,10 @@ func (a *Analyzer) detectJavaScriptFrameworks(path string, result *Result) {
 	// Check for Vue
 	if _, ok := deps["vue"]; ok {
 		result.Frameworks = append(result.Frameworks, Framework{
-			Name: "Vue",
-			Type: "frontend",
-			Port: 5173,
+			Name:  "Vue",
+			Files: []string{"package.json"},
+			Type:  "frontend",
+			Port:  5173,
 		})
 		a.logger.Debug("✓ Detected: Vue")
 		found = true
@@ -316,9 +406,10 @@ func (a *Analyzer) detectJavaScriptFrameworks(path string, result *Result) {
 	// Check for Express
 	if _, ok := deps["express"]; ok {
 		result.Frameworks = append(result.Frameworks, Framework{
-			Name: "Express",
-			Type: "web",
-			Port: 3000,
+			Name:  "Express",
+			Files: []string{"package.json"},
+			Type:  "web",
+			Port:  3000,
 		})
 		a.logger.Debug("✓ Detected: Express")
 		found = true
@@ -327,9 +418,10 @@ func (a *Analyzer) detectJavaScriptFrameworks(path string, result *Result) {
 	// Check for Fastify
 	if _, ok := deps["fastify"]; ok {
 		result.Frameworks = append(result.Frameworks, Framework{
-			Name: "Fastify",
-			Type: "web",
-			Port: 3000,
+			Name:  "Fastify",
+			Files: []string{"package.json"},
+			Type:  "web",
+			Port:  3000,
 		})
 		a.logger.Debug("✓ Detected: Fastify")
 		found = true
@@ -338,9 +430,10 @@ func (a *Analyzer) detectJavaScriptFrameworks(path string, result *Result) {
 	// Check for NestJS
 	if _, ok := deps["@nestjs/core"]; ok {
 		result.Frameworks = append(result.Frameworks, Framework{
-			Name: "NestJS",
-			Type: "web",
-			Port: 3000,
+			Name:  "NestJS",
+			Files: []string{"package.json"},
+			Type:  "web",
+			Port:  3000,
 		})
 		a.logger.Debug("✓ Detected: NestJS")
 		found = true
@@ -360,35 +453,39 @@ func (a *Analyzer) detectPythonFrameworks(path string, result *Result) {
 	if content, err := readFile(reqPath); err == nil {
 		if hasContent(content, "django") {
 			result.Frameworks = append(result.Frameworks, Framework{
-				Name: "Django",
-				Type: "web",
-				Port: 8000,
+				Name:  "Django",
+				Files: []string{"requirements.txt"},
+				Type:  "web",
+				Port:  8000,
 			})
 			a.logger.Debug("✓ Detected: Django")
 			found = true
 		}
 		if hasContent(content, "flask") {
 			result.Frameworks = append(result.Frameworks, Framework{
-				Name: "Flask",
-				Type: "web",
-				Port: 5000,
+				Name:  "Flask",
+				Files: []string{"requirements.txt"},
+				Type:  "web",
+				Port:  5000,
 			})
 			a.logger.Debug("✓ Detected: Flask")
 			found = true
 		}
 		if hasContent(content, "fastapi") {
 			result.Frameworks = append(result.Frameworks, Framework{
-				Name: "FastAPI",
-				Type: "web",
-				Port: 8000,
+				Name:  "FastAPI",
+				Files: []string{"requirements.txt"},
+				Type:  "web",
+				Port:  8000,
 			})
 			a.logger.Debug("✓ Detected: FastAPI")
 			found = true
 		}
 		if hasContent(content, "sqlalchemy") {
 			result.Frameworks = append(result.Frameworks, Framework{
-				Name: "SQLAlchemy",
-				Type: "orm",
+				Name:  "SQLAlchemy",
+				Files: []string{"requirements.txt"},
+				Type:  "orm",
 			})
 			a.logger.Debug("✓ Detected: SQLAlchemy")
 			found = true
@@ -400,27 +497,30 @@ func (a *Analyzer) detectPythonFrameworks(path string, result *Result) {
 	if content, err := readFile(pyprojPath); err == nil {
 		if hasContent(content, "django") && !found {
 			result.Frameworks = append(result.Frameworks, Framework{
-				Name: "Django",
-				Type: "web",
-				Port: 8000,
+				Name:  "Django",
+				Files: []string{"pyproject.toml"},
+				Type:  "web",
+				Port:  8000,
 			})
 			a.logger.Debug("✓ Detected: Django")
 			found = true
 		}
 		if hasContent(content, "flask") && !found {
 			result.Frameworks = append(result.Frameworks, Framework{
-				Name: "Flask",
-				Type: "web",
-				Port: 5000,
+				Name:  "Flask",
+				Files: []string{"pyproject.toml"},
+				Type:  "web",
+				Port:  5000,
 			})
 			a.logger.Debug("✓ Detected: Flask")
 			found = true
 		}
 		if hasContent(content, "fastapi") && !found {
 			result.Frameworks = append(result.Frameworks, Framework{
-				Name: "FastAPI",
-				Type: "web",
-				Port: 8000,
+				Name:  "FastAPI",
+				Files: []string{"pyproject.toml"},
+				Type:  "web",
+				Port:  8000,
 			})
 			a.logger.Debug("✓ Detected: FastAPI")
 			found = true
@@ -445,9 +545,10 @@ func (a *Analyzer) detectRustFrameworks(path string, result *Result) {
 
 	if hasContent(content, "actix-web") {
 		result.Frameworks = append(result.Frameworks, Framework{
-			Name: "Actix",
-			Type: "web",
-			Port: 8000,
+			Name:  "Actix",
+			Files: []string{"Cargo.toml"},
+			Type:  "web",
+			Port:  8000,
 		})
 		a.logger.Debug("✓ Detected: Actix")
 		found = true
@@ -455,9 +556,10 @@ func (a *Analyzer) detectRustFrameworks(path string, result *Result) {
 
 	if hasContent(content, "rocket") {
 		result.Frameworks = append(result.Frameworks, Framework{
-			Name: "Rocket",
-			Type: "web",
-			Port: 8000,
+			Name:  "Rocket",
+			Files: []string{"Cargo.toml"},
+			Type:  "web",
+			Port:  8000,
 		})
 		a.logger.Debug("✓ Detected: Rocket")
 		found = true
@@ -465,9 +567,10 @@ func (a *Analyzer) detectRustFrameworks(path string, result *Result) {
 
 	if hasContent(content, "axum") {
 		result.Frameworks = append(result.Frameworks, Framework{
-			Name: "Axum",
-			Type: "web",
-			Port: 8000,
+			Name:  "Axum",
+			Files: []string{"Cargo.toml"},
+			Type:  "web",
+			Port:  8000,
 		})
 		a.logger.Debug("✓ Detected: Axum")
 		found = true
@@ -478,31 +581,25 @@ func (a *Analyzer) detectRustFrameworks(path string, result *Result) {
 	}
 }
 
-// detectJavaFrameworks detects Java frameworks
+// detectJavaFrameworks detects Java and Kotlin frameworks
 func (a *Analyzer) detectJavaFrameworks(path string, result *Result) {
-	pomPath := filepath.Join(path, "pom.xml")
-	if content, err := readFile(pomPath); err == nil {
-		if hasContent(content, "spring-boot") {
-			result.Frameworks = append(result.Frameworks, Framework{
-				Name: "Spring Boot",
-				Type: "web",
-				Port: 8080,
-			})
-			a.logger.Debug("✓ Detected: Spring Boot")
+	for _, buildFile := range []string{"pom.xml", "build.gradle", "build.gradle.kts"} {
+		content, err := readFile(filepath.Join(path, buildFile))
+		if err != nil {
+			continue
 		}
-		return
-	}
 
-	gradlePath := filepath.Join(path, "build.gradle")
-	if content, err := readFile(gradlePath); err == nil {
-		if hasContent(content, "spring-boot") {
+		// Gradle applies the plugin as org.springframework.boot
+		if hasContent(content, "spring-boot") || hasContent(content, "org.springframework.boot") {
 			result.Frameworks = append(result.Frameworks, Framework{
-				Name: "Spring Boot",
-				Type: "web",
-				Port: 8080,
+				Name:  "Spring Boot",
+				Files: []string{buildFile},
+				Type:  "web",
+				Port:  8080,
 			})
 			a.logger.Debug("✓ Detected: Spring Boot")
 		}
+		return
 	}
 }
 
@@ -519,9 +616,10 @@ func (a *Analyzer) detectRubyFrameworks(path string, result *Result) {
 
 	if hasContent(content, "rails") {
 		result.Frameworks = append(result.Frameworks, Framework{
-			Name: "Rails",
-			Type: "web",
-			Port: 3000,
+			Name:  "Rails",
+			Files: []string{"Gemfile"},
+			Type:  "web",
+			Port:  3000,
 		})
 		a.logger.Debug("✓ Detected: Rails")
 		found = true
@@ -529,9 +627,10 @@ func (a *Analyzer) detectRubyFrameworks(path string, result *Result) {
 
 	if hasContent(content, "sinatra") {
 		result.Frameworks = append(result.Frameworks, Framework{
-			Name: "Sinatra",
-			Type: "web",
-			Port: 4567,
+			Name:  "Sinatra",
+			Files: []string{"Gemfile"},
+			Type:  "web",
+			Port:  4567,
 		})
 		a.logger.Debug("✓ Detected: Sinatra")
 		found = true
@@ -542,6 +641,154 @@ func (a *Analyzer) detectRubyFrameworks(path string, result *Result) {
 	}
 }
 
+// detectPHPFrameworks detects PHP frameworks
+func (a *Analyzer) detectPHPFrameworks(path string, result *Result) {
+	composerPath := filepath.Join(path, "composer.json")
+	content, err := readFile(composerPath)
+	if err != nil {
+		a.logger.Debug("Could not read composer.json: %v", err)
+		return
+	}
+
+	found := false
+
+	if hasContent(content, "laravel/framework") {
+		result.Frameworks = append(result.Frameworks, Framework{
+			Name:  "Laravel",
+			Files: []string{"composer.json"},
+			Type:  "web",
+			Port:  8000,
+		})
+		a.logger.Debug("✓ Detected: Laravel")
+		found = true
+	}
+
+	if hasContent(content, "symfony/framework-bundle") {
+		result.Frameworks = append(result.Frameworks, Framework{
+			Name:  "Symfony",
+			Files: []string{"composer.json"},
+			Type:  "web",
+			Port:  8000,
+		})
+		a.logger.Debug("✓ Detected: Symfony")
+		found = true
+	}
+
+	if !found {
+		a.logger.Debug("No PHP frameworks detected")
+	}
+}
+
+// detectElixirFrameworks detects Elixir frameworks
+func (a *Analyzer) detectElixirFrameworks(path string, result *Result) {
+	content, err := readFile(filepath.Join(path, "mix.exs"))
+	if err != nil {
+		a.logger.Debug("Could not read mix.exs: %v", err)
+		return
+	}
+
+	if hasContent(content, "{:phoenix,") {
+		result.Frameworks = append(result.Frameworks, Framework{
+			Name:  "Phoenix",
+			Files: []string{"mix.exs"},
+			Type:  "web",
+			Port:  4000,
+		})
+		a.logger.Debug("✓ Detected: Phoenix")
+		return
+	}
+
+	a.logger.Debug("No Elixir frameworks detected")
+}
+
+// detectDotnetFrameworks detects .NET frameworks
+func (a *Analyzer) detectDotnetFrameworks(path string, result *Result) {
+	for _, project := range dotnetProjects(path) {
+		content, err := readFile(project)
+		if err != nil {
+			continue
+		}
+		if hasContent(content, "Microsoft.NET.Sdk.Web") {
+			rel, _ := filepath.Rel(path, project)
+			result.Frameworks = append(result.Frameworks, Framework{
+				Name:  "ASP.NET Core",
+				Files: []string{rel},
+				Type:  "web",
+				Port:  5000,
+			})
+			a.logger.Debug("✓ Detected: ASP.NET Core")
+			return
+		}
+	}
+
+	a.logger.Debug("No .NET frameworks detected")
+}
+
+// detectDartFrameworks detects Dart frameworks
+func (a *Analyzer) detectDartFrameworks(path string, result *Result) {
+	content, err := readFile(filepath.Join(path, "pubspec.yaml"))
+	if err != nil {
+		a.logger.Debug("Could not read pubspec.yaml: %v", err)
+		return
+	}
+
+	if hasContent(content, "sdk: flutter") {
+		result.Frameworks = append(result.Frameworks, Framework{
+			Name:  "Flutter",
+			Files: []string{"pubspec.yaml"},
+			Type:  "frontend",
+		})
+		a.logger.Debug("✓ Detected: Flutter")
+		return
+	}
+
+	a.logger.Debug("No Dart frameworks detected")
+}
+
+// detectDenoFrameworks detects Deno frameworks
+func (a *Analyzer) detectDenoFrameworks(path string, result *Result) {
+	for _, name := range []string{"deno.json", "deno.jsonc"} {
+		content, err := readFile(filepath.Join(path, name))
+		if err != nil {
+			continue
+		}
+		if hasContent(content, "$fresh/") || hasContent(content, "@fresh/core") {
+			result.Frameworks = append(result.Frameworks, Framework{
+				Name:  "Fresh",
+				Files: []string{name},
+				Type:  "web",
+				Port:  8000,
+			})
+			a.logger.Debug("✓ Detected: Fresh")
+			return
+		}
+	}
+
+	a.logger.Debug("No Deno frameworks detected")
+}
+
+// detectSwiftFrameworks detects Swift frameworks
+func (a *Analyzer) detectSwiftFrameworks(path string, result *Result) {
+	content, err := readFile(filepath.Join(path, "Package.swift"))
+	if err != nil {
+		a.logger.Debug("Could not read Package.swift: %v", err)
+		return
+	}
+
+	if hasContent(content, "vapor/vapor") {
+		result.Frameworks = append(result.Frameworks, Framework{
+			Name:  "Vapor",
+			Files: []string{"Package.swift"},
+			Type:  "web",
+			Port:  8080,
+		})
+		a.logger.Debug("✓ Detected: Vapor")
+		return
+	}
+
+	a.logger.Debug("No Swift frameworks detected")
+}
+
 // ============================================================================
 // DOCKER DETECTION
 // ============================================================================
@@ -552,6 +799,7 @@ func (a *Analyzer) detectDocker(path string, result *Result) error {
 	dockerfilePath := filepath.Join(path, "Dockerfile")
 	if fileExists(dockerfilePath) {
 		result.DockerDetected = true
+		result.DockerFiles = append(result.DockerFiles, "Dockerfile")
 		a.logger.Debug("Found Dockerfile")
 	}
 
@@ -559,6 +807,7 @@ func (a *Analyzer) detectDocker(path string, result *Result) error {
 	composePath := filepath.Join(path, "docker-compose.yml")
 	if fileExists(composePath) {
 		result.DockerDetected = true
+		result.DockerFiles = append(result.DockerFiles, "docker-compose.yml")
 		a.logger.Debug("Found docker-compose.yml")
 		a.parseDockerCompose(composePath, result)
 	}
@@ -567,6 +816,7 @@ func (a *Analyzer) detectDocker(path string, result *Result) error {
 	composeYamlPath := filepath.Join(path, "docker-compose.yaml")
 	if fileExists(composeYamlPath) {
 		result.DockerDetected = true
+		result.DockerFiles = append(result.DockerFiles, "docker-compose.yaml")
 		a.logger.Debug("Found docker-compose.yaml")
 		a.parseDockerCompose(composeYamlPath, result)
 	}
@@ -584,6 +834,7 @@ func (a *Analyzer) parseDockerCompose(path string, result *Result) {
 
 	lines := strings.Split(content, "\n")
 	inServices := false
+	serviceIndent := 0
 
 	for _, line := range lines {
 		trimmed := strings.TrimSpace(line)
@@ -606,8 +857,13 @@ func (a *Analyzer) parseDockerCompose(path string, result *Result) {
 				continue
 			}
 
-			// Service names have exactly 2 spaces indentation (not 4+)
-			if len(line) >= 2 && line[0:1] == " " && line[1:2] != " " && strings.HasSuffix(trimmed, ":") {
+			// Service names sit at the indentation of the first key under
+			// services:, their settings are nested deeper
+			indent := len(line) - len(strings.TrimLeft(line, " \t"))
+			if serviceIndent == 0 {
+				serviceIndent = indent
+			}
+			if indent == serviceIndent && strings.HasSuffix(trimmed, ":") {
 				serviceName := strings.TrimSpace(line)
 				serviceName = strings.TrimSuffix(serviceName, ":")
 				if serviceName != "" && !strings.Contains(serviceName, " ") {
@@ -646,9 +902,14 @@ func (a *Analyzer) analyzeProjectStructure(path string, result *Result) error {
 	a.findTestDirs(path, result)
 	a.findBuildDirs(path, result)
 	result.HasVendor = dirExists(filepath.Join(path, "vendor"))
+	result.HasMakefile = fileExists(filepath.Join(path, "Makefile"))
 	a.findMainEntrypoint(path, result)
 	a.findDependencyFiles(path, result)
 	a.findConfigFiles(path, result)
+	a.findLintTools(path, result)
+	a.findTestFrameworks(path, result)
+	a.findGenerators(path, result)
+	a.findMigrations(path, result)
 	return nil
 }
 
@@ -721,9 +982,17 @@ func (a *Analyzer) findBuildDirs(path string, result *Result) {
 func (a *Analyzer) findMainEntrypoint(path string, result *Result) {
 	switch result.Language {
 	case "go":
+		result.Binaries = findGoBinaries(path)
+		if len(result.Binaries) > 0 {
+			a.logger.Debug("Found Go main packages: %v", result.Binaries)
+		}
+
 		if fileExists(filepath.Join(path, "main.go")) {
 			result.MainEntrypoint = "main.go"
 			a.logger.Debug("Found Go entrypoint: main.go")
+		} else if len(result.Binaries) > 0 {
+			result.MainEntrypoint = result.Binaries[0]
+			a.logger.Debug("Found Go entrypoint: %s", result.MainEntrypoint)
 		}
 
 	case "javascript", "typescript":
@@ -758,10 +1027,39 @@ func (a *Analyzer) findMainEntrypoint(path string, result *Result) {
 			a.logger.Debug("Found Rust entrypoint: src/main.rs")
 		}
 
-	case "java":
-		if fileExists(filepath.Join(path, "src", "main", "java")) {
-			result.MainEntrypoint = "src/main/java"
-			a.logger.Debug("Found Java entrypoint directory")
+	case "java", "kotlin":
+		for _, ep := range []string{"src/main/java", "src/main/kotlin"} {
+			if dirExists(filepath.Join(path, filepath.FromSlash(ep))) {
+				result.MainEntrypoint = ep
+				a.logger.Debug("Found JVM entrypoint directory: %s", ep)
+				return
+			}
+		}
+
+	case "php":
+		entryPoints := []string{"public/index.php", "index.php"}
+		for _, ep := range entryPoints {
+			if fileExists(filepath.Join(path, filepath.FromSlash(ep))) {
+				result.MainEntrypoint = ep
+				a.logger.Debug("Found PHP entrypoint: %s", ep)
+				return
+			}
+		}
+
+	case "deno":
+		entryPoints := []string{"main.ts", "mod.ts", "main.js", "mod.js"}
+		for _, ep := range entryPoints {
+			if fileExists(filepath.Join(path, ep)) {
+				result.MainEntrypoint = ep
+				a.logger.Debug("Found Deno entrypoint: %s", ep)
+				return
+			}
+		}
+
+	case "dart":
+		if files, _ := utils.FindFiles(filepath.Join(path, "bin"), []string{".dart"}); len(files) > 0 {
+			result.MainEntrypoint = "bin/" + filepath.Base(files[0])
+			a.logger.Debug("Found Dart entrypoint: %s", result.MainEntrypoint)
 		}
 
 	case "ruby":
@@ -781,6 +1079,7 @@ func (a *Analyzer) findDependencyFiles(path string, result *Result) {
 	depFiles := []string{
 		"go.mod",
 		"go.sum",
+		"go.work",
 		"package.json",
 		"package-lock.json",
 		"yarn.lock",
@@ -795,8 +1094,26 @@ func (a *Analyzer) findDependencyFiles(path string, result *Result) {
 		"Cargo.lock",
 		"pom.xml",
 		"build.gradle",
+		"build.gradle.kts",
+		"settings.gradle",
+		"settings.gradle.kts",
 		"composer.json",
 		"composer.lock",
+		"CMakeLists.txt",
+		"meson.build",
+		"configure.ac",
+		"mix.exs",
+		"mix.lock",
+		"pubspec.yaml",
+		"pubspec.lock",
+		"deno.json",
+		"deno.lock",
+		"build.zig",
+		"build.zig.zon",
+		"Package.swift",
+		"Package.resolved",
+		"stack.yaml",
+		"cabal.project",
 	}
 
 	for _, depFile := range depFiles {
@@ -806,6 +1123,18 @@ func (a *Analyzer) findDependencyFiles(path string, result *Result) {
 			a.logger.Debug("Found dependency file: %s", depFile)
 		}
 	}
+
+	// The lockfile tells which package manager a JavaScript project uses
+	if result.Language == "javascript" || result.Language == "typescript" {
+		switch {
+		case fileExists(filepath.Join(path, "pnpm-lock.yaml")):
+			result.PackageManager = "pnpm"
+		case fileExists(filepath.Join(path, "yarn.lock")):
+			result.PackageManager = "yarn"
+		default:
+			result.PackageManager = "npm"
+		}
+	}
 }
 
 // findConfigFiles finds configuration files
@@ -818,11 +1147,44 @@ func (a *Analyzer) findConfigFiles(path string, result *Result) {
 		"config.yml",
 		"config.json",
 		".eslintrc",
+		".eslintrc.js",
+		".eslintrc.cjs",
 		".eslintrc.json",
+		".eslintrc.yml",
+		".eslintrc.yaml",
+		"eslint.config.js",
+		"eslint.config.mjs",
+		"eslint.config.cjs",
+		"eslint.config.ts",
 		".prettierrc",
+		".prettierrc.json",
+		".prettierrc.yml",
+		".prettierrc.yaml",
+		".prettierrc.js",
+		".prettierrc.cjs",
+		"prettier.config.js",
+		"prettier.config.mjs",
 		"jest.config.js",
 		"tsconfig.json",
+		".golangci.yml",
+		".golangci.yaml",
+		".golangci.toml",
+		".golangci.json",
+		"ruff.toml",
+		".ruff.toml",
 		".pylintrc",
+		"pylintrc",
+		"mypy.ini",
+		".mypy.ini",
+		"rustfmt.toml",
+		".rustfmt.toml",
+		"clippy.toml",
+		".rubocop.yml",
+		".php-cs-fixer.php",
+		".php-cs-fixer.dist.php",
+		"phpunit.xml",
+		"phpunit.xml.dist",
+		"CMakePresets.json",
 		"setup.cfg",
 		"tox.ini",
 		".gitignore",
@@ -854,6 +1216,17 @@ func fileExists(path string) bool {
 	return err == nil
 }
 
+// existingFiles returns the names that exist in dir
+func existingFiles(dir string, names ...string) []string {
+	var found []string
+	for _, name := range names {
+		if fileExists(filepath.Join(dir, name)) {
+			found = append(found, name)
+		}
+	}
+	return found
+}
+
 // dirExists checks if a directory exists
 func dirExists(path string) bool {
 	info, err := os.Stat(path)
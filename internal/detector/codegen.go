@@ -0,0 +1,109 @@
+package detector
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Generator is a detected code generation tool
+type Generator struct {
+	Name   string // "go-generate", "buf", "protoc", "sqlc", "openapi", "gqlgen", "mockery"
+	Config string // config or spec file the tool reads
+}
+
+// skipDirs are never descended into when scanning sources
+var skipDirs = map[string]bool{
+	"vendor":       true,
+	"node_modules": true,
+	"testdata":     true,
+	"target":       true,
+	"dist":         true,
+	"build":        true,
+}
+
+// ============================================================================
+// CODE GENERATION DETECTION
+// ============================================================================
+
+// findGenerators detects code generators from their config files and, for
+// Go, from //go:generate directives
+func (a *Analyzer) findGenerators(path string, result *Result) {
+	configs := []struct {
+		name  string
+		files []string
+	}{
+		{name: "buf", files: []string{"buf.gen.yaml", "buf.gen.yml", "buf.yaml"}},
+		{name: "sqlc", files: []string{"sqlc.yaml", "sqlc.yml", "sqlc.json"}},
+		{name: "openapi", files: []string{"openapi.yaml", "openapi.yml", "openapi.json", "api/openapi.yaml", "api/openapi.yml"}},
+		{name: "gqlgen", files: []string{"gqlgen.yml", "gqlgen.yaml"}},
+		{name: "mockery", files: []string{".mockery.yaml", ".mockery.yml"}},
+	}
+
+	for _, cfg := range configs {
+		for _, file := range cfg.files {
+			if fileExists(filepath.Join(path, filepath.FromSlash(file))) {
+				result.Generators = append(result.Generators, Generator{Name: cfg.name, Config: file})
+				a.logger.Debug("Found code generator: %s (%s)", cfg.name, file)
+				break
+			}
+		}
+	}
+
+	hasBuf := false
+	for _, gen := range result.Generators {
+		hasBuf = hasBuf || gen.Name == "buf"
+	}
+
+	goGenerate, protos := false, false
+
+	_ = walkSources(path, func(file string) {
+		switch filepath.Ext(file) {
+		case ".proto":
+			protos = true
+		case ".go":
+			if !goGenerate && result.Language == "go" {
+				if content, err := readFile(file); err == nil && strings.Contains(content, "//go:generate ") {
+					goGenerate = true
+				}
+			}
+		}
+	})
+
+	// Plain .proto files without buf fall back to protoc
+	if protos && !hasBuf {
+		result.Generators = append(result.Generators, Generator{Name: "protoc"})
+		a.logger.Debug("Found code generator: protoc")
+	}
+	if goGenerate {
+		result.Generators = append([]Generator{{Name: "go-generate"}}, result.Generators...)
+		a.logger.Debug("Found code generator: go:generate directives")
+	}
+}
+
+// walkSources calls fn for every file in the project, skipping hidden,
+// vendored and build output directories
+func walkSources(root string, fn func(path string)) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() && path != root {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || skipDirs[name]) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if d.Type()&os.ModeSymlink == 0 {
+			fn(path)
+		}
+		return nil
+	})
+}
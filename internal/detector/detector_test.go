@@ -1,7 +1,266 @@
 package detector
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
-func TestDetector(t *testing.T) {
-	t.Skip("Implementation pending")
+func TestFindGenerators(t *testing.T) {
+	result := analyze(t, map[string]string{
+		"go.mod":                  "module example.com/svc\n",
+		"sqlc.yaml":               "version: \"2\"\n",
+		"api/openapi.yaml":        "openapi: 3.0.0\n",
+		"gqlgen.yml":              "schema: graph/*.graphqls\n",
+		".mockery.yaml":           "with-expecter: true\n",
+		"proto/svc/v1/svc.proto":  "syntax = \"proto3\";\n",
+		"internal/store/store.go": "package store\n\n//go:generate stringer -type=Kind\n",
+		"vendor/x/y.go":           "package y\n//go:generate ignored\n",
+		"node_modules/p/p.proto":  "",
+	})
+
+	want := []Generator{
+		{Name: "go-generate"},
+		{Name: "sqlc", Config: "sqlc.yaml"},
+		{Name: "openapi", Config: "api/openapi.yaml"},
+		{Name: "gqlgen", Config: "gqlgen.yml"},
+		{Name: "mockery", Config: ".mockery.yaml"},
+		{Name: "protoc"},
+	}
+	if !reflect.DeepEqual(result.Generators, want) {
+		t.Errorf("Generators = %+v\nwant %+v", result.Generators, want)
+	}
+}
+
+func TestFindGeneratorsPrefersBuf(t *testing.T) {
+	result := analyze(t, map[string]string{
+		"package.json":  "{}",
+		"buf.gen.yaml":  "version: v2\n",
+		"proto/a.proto": "syntax = \"proto3\";\n",
+	})
+
+	want := []Generator{{Name: "buf", Config: "buf.gen.yaml"}}
+	if !reflect.DeepEqual(result.Generators, want) {
+		t.Errorf("Generators = %+v, want %+v", result.Generators, want)
+	}
+}
+
+func TestFindMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		tool    string
+		dir     string
+		service string
+	}{
+		{
+			name: "golang-migrate with compose postgres",
+			files: map[string]string{
+				"go.mod":                          "module example.com/svc\n",
+				"migrations/000001_init.up.sql":   "CREATE TABLE t ();\n",
+				"migrations/000001_init.down.sql": "DROP TABLE t;\n",
+				"docker-compose.yml":              "services:\n  api:\n    build: .\n  postgres:\n    image: postgres:16\n",
+			},
+			tool: "golang-migrate", dir: "migrations", service: "postgres",
+		},
+		{
+			name: "goose",
+			files: map[string]string{
+				"go.mod":                     "module example.com/svc\n",
+				"db/migrations/001_init.sql": "-- +goose Up\nCREATE TABLE t ();\n",
+			},
+			tool: "goose", dir: "db/migrations",
+		},
+		{name: "alembic", files: map[string]string{"requirements.txt": "sqlalchemy\n", "alembic.ini": ""}, tool: "alembic", dir: "alembic"},
+		{name: "django", files: map[string]string{"requirements.txt": "django\n", "manage.py": ""}, tool: "django"},
+		{name: "prisma", files: map[string]string{"package.json": "{}", "prisma/schema.prisma": ""}, tool: "prisma", dir: "prisma/migrations"},
+		{name: "rails", files: map[string]string{"Gemfile": "gem 'rails'\n", "db/migrate/001_init.rb": ""}, tool: "rails", dir: "db/migrate"},
+		{name: "flyway", files: map[string]string{"pom.xml": "<project/>", "src/main/resources/db/migration/V1__init.sql": ""}, tool: "flyway", dir: "src/main/resources/db/migration"},
+		{name: "none", files: map[string]string{"go.mod": "module x\n", "migrations/README.md": ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := analyze(t, tt.files)
+			if result.MigrationTool != tt.tool || result.MigrationDir != tt.dir || result.DatabaseService != tt.service {
+				t.Errorf("got tool=%q dir=%q service=%q", result.MigrationTool, result.MigrationDir, result.DatabaseService)
+			}
+		})
+	}
+}
+
+func TestFindLintTools(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		wantLint   []string
+		wantFormat []string
+	}{
+		{
+			name:       "go with golangci config",
+			files:      map[string]string{"go.mod": "module x\n", ".golangci.yml": "linters: {}\n"},
+			wantLint:   []string{"golangci-lint"},
+			wantFormat: []string{"gofmt"},
+		},
+		{
+			name:       "node with eslint and prettier",
+			files:      map[string]string{"package.json": "{}", "eslint.config.mjs": "", ".prettierrc": "{}"},
+			wantLint:   []string{"eslint"},
+			wantFormat: []string{"prettier"},
+		},
+		{
+			name: "python pyproject tables",
+			files: map[string]string{
+				"pyproject.toml": "[project]\nname = \"x\"\n\n[tool.ruff]\nline-length = 100\n\n[tool.mypy]\nstrict = true\n",
+			},
+			wantLint:   []string{"ruff", "mypy"},
+			wantFormat: []string{"ruff-format"},
+		},
+		{
+			name:       "rust toolchain",
+			files:      map[string]string{"Cargo.toml": "[package]\nname = \"x\"\n"},
+			wantLint:   []string{"clippy"},
+			wantFormat: []string{"rustfmt"},
+		},
+		{
+			name:  "node without config",
+			files: map[string]string{"package.json": "{}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := analyze(t, tt.files)
+			if !reflect.DeepEqual(result.LintTools, tt.wantLint) {
+				t.Errorf("LintTools = %v, want %v", result.LintTools, tt.wantLint)
+			}
+			if !reflect.DeepEqual(result.FormatTools, tt.wantFormat) {
+				t.Errorf("FormatTools = %v, want %v", result.FormatTools, tt.wantFormat)
+			}
+		})
+	}
+}
+
+func TestGolangciConfigVersion(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+		want    bool
+	}{
+		{file: ".golangci.yml", content: "linters:\n  enable: [govet]\n"},
+		{file: ".golangci.yaml", content: "version: \"2\"\nlinters:\n  default: standard\n", want: true},
+		{file: ".golangci.toml", content: "version = '2'\n", want: true},
+		{file: ".golangci.json", content: "{\n  \"version\": \"2\",\n  \"linters\": {}\n}\n", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			result := analyze(t, map[string]string{"go.mod": "module x\n", tt.file: tt.content})
+			if result.GolangciLintV2 != tt.want {
+				t.Errorf("GolangciLintV2 = %v, want %v", result.GolangciLintV2, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoPackageTestsCountAsTests(t *testing.T) {
+	result := analyze(t, map[string]string{
+		"go.mod":                       "module x\n",
+		"cmd/api/main.go":              "package main\n",
+		"internal/store/store.go":      "package store\n",
+		"internal/store/store_test.go": "package store\n",
+	})
+	if !result.TestDirFound || result.TestFramework != "go test" {
+		t.Errorf("TestDirFound = %v, TestFramework = %q, want true and go test", result.TestDirFound, result.TestFramework)
+	}
+
+	if result := analyze(t, map[string]string{"go.mod": "module x\n", "main.go": "package main\n"}); result.TestDirFound {
+		t.Error("TestDirFound set without any test files")
+	}
+}
+
+func TestFindTestFrameworks(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		want        string
+		e2e         string
+		integration bool
+	}{
+		{
+			name:        "go integration build tag",
+			files:       map[string]string{"go.mod": "module x\n", "store/db_test.go": "//go:build integration\n\npackage store\n"},
+			want:        "go test",
+			integration: true,
+		},
+		{
+			name:  "vitest with playwright",
+			files: map[string]string{"package.json": `{"devDependencies": {"vitest": "^2", "@playwright/test": "^1"}}`},
+			want:  "vitest",
+			e2e:   "playwright",
+		},
+		{
+			name:  "mocha with cypress config",
+			files: map[string]string{"package.json": "{}", ".mocharc.yml": "spec: test\n", "cypress.config.ts": ""},
+			want:  "mocha",
+			e2e:   "cypress",
+		},
+		{
+			name: "pytest markers",
+			files: map[string]string{
+				"pyproject.toml": "[tool.pytest.ini_options]\nmarkers = [\"integration: needs services\"]\n",
+			},
+			want:        "pytest",
+			integration: true,
+		},
+		{
+			name:  "python unittest",
+			files: map[string]string{"requirements.txt": "flask\n", "tests/test_app.py": "import unittest\n"},
+			want:  "unittest",
+		},
+		{
+			name:  "ruby minitest",
+			files: map[string]string{"Gemfile": "source 'https://rubygems.org'\n", "test/app_test.rb": ""},
+			want:  "minitest",
+		},
+		{
+			name:        "cargo nextest",
+			files:       map[string]string{"Cargo.toml": "[package]\n", ".config/nextest.toml": "", "tests/api.rs": ""},
+			want:        "cargo-nextest",
+			integration: true,
+		},
+		{
+			name:  "junit",
+			files: map[string]string{"pom.xml": "<dependency><artifactId>junit-jupiter</artifactId></dependency>"},
+			want:  "junit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := analyze(t, tt.files)
+			if result.TestFramework != tt.want || result.E2EFramework != tt.e2e || result.IntegrationTests != tt.integration {
+				t.Errorf("got (%q, %q, %v), want (%q, %q, %v)",
+					result.TestFramework, result.E2EFramework, result.IntegrationTests, tt.want, tt.e2e, tt.integration)
+			}
+		})
+	}
+}
+
+func TestFindBenchmarks(t *testing.T) {
+	result := analyze(t, map[string]string{
+		"go.mod":          "module x\n",
+		"codec/x_test.go": "package codec\n\nfunc BenchmarkEncode(b *testing.B) {}\n",
+		"store/x_test.go": "package store\n\nfunc TestGet(t *testing.T) {}\n",
+	})
+	if !result.HasBenchmarks {
+		t.Error("Go benchmark not detected")
+	}
+	if !reflect.DeepEqual(result.BenchPackages, []string{"./codec"}) {
+		t.Errorf("BenchPackages = %v, want [./codec]", result.BenchPackages)
+	}
+
+	result = analyze(t, map[string]string{"Cargo.toml": "[package]\n"})
+	if result.HasBenchmarks {
+		t.Error("benchmarks detected without benches/")
+	}
 }
@@ -1,7 +1,29 @@
 package detector
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
-func TestDockerDetection(t *testing.T) {
-	t.Skip("Implementation pending")
+func TestParseDockerComposeServices(t *testing.T) {
+	result := analyze(t, map[string]string{
+		"docker-compose.yml": `version: "3.9"
+services:
+  api:
+    build: .
+    depends_on:
+      - db
+  db:
+    image: postgres:16
+volumes:
+  data:
+`,
+	})
+
+	if !result.DockerDetected {
+		t.Fatal("DockerDetected = false")
+	}
+	if want := []string{"api", "db"}; !reflect.DeepEqual(result.DockerServices, want) {
+		t.Errorf("DockerServices = %v, want %v", result.DockerServices, want)
+	}
 }
@@ -1,7 +1,51 @@
 package detector
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
-func TestFrameworkDetection(t *testing.T) {
-	t.Skip("Implementation pending")
+func TestDetectPHPFrameworks(t *testing.T) {
+	tests := []struct {
+		name    string
+		require string
+		want    string
+	}{
+		{name: "laravel", require: `"laravel/framework": "^11.0"`, want: "Laravel"},
+		{name: "symfony", require: `"symfony/framework-bundle": "7.1.*"`, want: "Symfony"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := analyze(t, map[string]string{
+				"composer.json": `{"require": {` + tt.require + `}}`,
+			})
+
+			if result.Language != "php" {
+				t.Fatalf("Language = %q, want php", result.Language)
+			}
+			if len(result.Frameworks) != 1 || result.Frameworks[0].Name != tt.want {
+				t.Fatalf("Frameworks = %+v, want %s", result.Frameworks, tt.want)
+			}
+			if files := result.Frameworks[0].Files; !reflect.DeepEqual(files, []string{"composer.json"}) {
+				t.Errorf("Files = %v, want [composer.json]", files)
+			}
+		})
+	}
+}
+
+func TestDetectionEvidence(t *testing.T) {
+	result := analyze(t, map[string]string{
+		"pyproject.toml":   "[project]\ndependencies = [\"fastapi\"]\n",
+		"requirements.txt": "pytest\n",
+		"package.json":     "{}",
+	})
+
+	// package.json is a dependency file here, not what made it Python
+	if want := []string{"requirements.txt", "pyproject.toml"}; !reflect.DeepEqual(result.LanguageFiles, want) {
+		t.Errorf("LanguageFiles = %v, want %v", result.LanguageFiles, want)
+	}
+	if len(result.Frameworks) != 1 || !reflect.DeepEqual(result.Frameworks[0].Files, []string{"pyproject.toml"}) {
+		t.Errorf("Frameworks = %+v, want FastAPI from pyproject.toml", result.Frameworks)
+	}
 }
@@ -1 +1,357 @@
 package detector
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/utils"
+)
+
+var (
+	mavenModuleRe    = regexp.MustCompile(`<module>\s*([^<\s]+)\s*</module>`)
+	gradleIncludeRe  = regexp.MustCompile(`(?m)^\s*include\s*\(?([^)\n]*)\)?`)
+	gradleProjectRe  = regexp.MustCompile(`["']([^"']+)["']`)
+	kotlinBuildHints = []string{"org.jetbrains.kotlin", "kotlin-maven-plugin", "kotlin-stdlib", "kotlin(\"jvm\")"}
+	// Project("{type}") = "Web", "Web\Web.csproj", "{guid}"
+	slnProjectRe = regexp.MustCompile(`(?m)^Project\("[^"]*"\)\s*=\s*"[^"]*"\s*,\s*"([^"]+\.[cf]sproj)"`)
+)
+
+// ============================================================================
+// JVM (JAVA / KOTLIN)
+// ============================================================================
+
+// detectJVMBuild inspects a Maven or Gradle project: build tool, wrapper
+// script, Kotlin sources and multi-module layout
+func (a *Analyzer) detectJVMBuild(path string, result *Result) {
+	result.Language = "java"
+
+	var buildFile string
+	if fileExists(filepath.Join(path, "pom.xml")) {
+		result.BuildTool = "maven"
+		result.HasWrapper = fileExists(filepath.Join(path, "mvnw"))
+		buildFile = "pom.xml"
+	} else {
+		result.BuildTool = "gradle"
+		result.HasWrapper = fileExists(filepath.Join(path, "gradlew"))
+		buildFile = "build.gradle"
+		if fileExists(filepath.Join(path, "build.gradle.kts")) {
+			buildFile = "build.gradle.kts"
+		}
+	}
+	a.logger.Debug("JVM build tool: %s (wrapper: %v)", result.BuildTool, result.HasWrapper)
+
+	result.LanguageFiles = []string{buildFile}
+
+	content, _ := readFile(filepath.Join(path, buildFile))
+	if dirExists(filepath.Join(path, "src", "main", "kotlin")) || hasAnyContent(content, kotlinBuildHints) {
+		result.Language = "kotlin"
+	}
+
+	switch result.BuildTool {
+	case "maven":
+		for _, m := range mavenModuleRe.FindAllStringSubmatch(content, -1) {
+			result.Modules = append(result.Modules, m[1])
+		}
+	case "gradle":
+		result.Modules = a.gradleModules(path)
+	}
+	if len(result.Modules) > 0 {
+		a.logger.Debug("Found %d build modules: %v", len(result.Modules), result.Modules)
+	}
+}
+
+// gradleModules lists the projects included from settings.gradle(.kts)
+func (a *Analyzer) gradleModules(path string) []string {
+	var modules []string
+
+	for _, name := range []string{"settings.gradle", "settings.gradle.kts"} {
+		content, err := readFile(filepath.Join(path, name))
+		if err != nil {
+			continue
+		}
+
+		for _, include := range gradleIncludeRe.FindAllStringSubmatch(content, -1) {
+			for _, project := range gradleProjectRe.FindAllStringSubmatch(include[1], -1) {
+				modules = append(modules, strings.TrimPrefix(project[1], ":"))
+			}
+		}
+	}
+
+	return removeDuplicates(modules)
+}
+
+// hasAnyContent checks if content contains any of the given strings
+func hasAnyContent(content string, searches []string) bool {
+	for _, search := range searches {
+		if hasContent(content, search) {
+			return true
+		}
+	}
+	return false
+}
+
+// ============================================================================
+// C / C++
+// ============================================================================
+
+// cSourceExts are the file extensions counted as C/C++ evidence
+var cSourceExts = []string{".c", ".cc", ".cpp", ".cxx", ".h", ".hh", ".hpp"}
+
+// detectCBuild detects C/C++ from a build system or source files. A bare
+// Makefile is not evidence: it is usually the one makegen generated.
+func (a *Analyzer) detectCBuild(path string, result *Result) bool {
+	switch {
+	case fileExists(filepath.Join(path, "CMakeLists.txt")):
+		result.BuildTool = "cmake"
+		result.LanguageFiles = []string{"CMakeLists.txt"}
+		result.BuildPresets = a.cmakePresetDirs(path)
+	case fileExists(filepath.Join(path, "meson.build")):
+		result.BuildTool = "meson"
+		result.LanguageFiles = []string{"meson.build"}
+	case fileExists(filepath.Join(path, "configure.ac")) ||
+		fileExists(filepath.Join(path, "configure.in")):
+		result.BuildTool = "autotools"
+		result.LanguageFiles = existingFiles(path, "configure.ac", "configure.in")
+	case !hasCSources(path) && !hasCSources(filepath.Join(path, "src")):
+		return false
+	}
+
+	result.Language = "cpp"
+	a.logger.Debug("C/C++ build system: %q", result.BuildTool)
+	return true
+}
+
+// cmakePresetDirs maps each configure preset in CMakePresets.json to its
+// binaryDir relative to the project, following inherits. Presets whose
+// directory uses macros other than ${sourceDir} and ${presetName} are left
+// out, since make cannot expand them.
+func (a *Analyzer) cmakePresetDirs(path string) map[string]string {
+	content, err := readFile(filepath.Join(path, "CMakePresets.json"))
+	if err != nil {
+		return nil
+	}
+	var presets struct {
+		ConfigurePresets []struct {
+			Name      string          `json:"name"`
+			Hidden    bool            `json:"hidden"`
+			BinaryDir string          `json:"binaryDir"`
+			Inherits  json.RawMessage `json:"inherits"`
+		} `json:"configurePresets"`
+	}
+	if err := json.Unmarshal([]byte(content), &presets); err != nil {
+		a.logger.Debug("Could not parse CMakePresets.json: %v", err)
+		return nil
+	}
+
+	binaryDirs := map[string]string{}
+	parents := map[string][]string{}
+	for _, preset := range presets.ConfigurePresets {
+		binaryDirs[preset.Name] = preset.BinaryDir
+		// inherits is a single name or a list of names
+		var one string
+		if json.Unmarshal(preset.Inherits, &one) == nil {
+			parents[preset.Name] = []string{one}
+		} else {
+			var many []string
+			_ = json.Unmarshal(preset.Inherits, &many)
+			parents[preset.Name] = many
+		}
+	}
+	// binaryDir comes from the preset itself or the first parent that sets it
+	var lookup func(name string, depth int) string
+	lookup = func(name string, depth int) string {
+		if dir := binaryDirs[name]; dir != "" || depth > len(binaryDirs) {
+			return dir
+		}
+		for _, parent := range parents[name] {
+			if dir := lookup(parent, depth+1); dir != "" {
+				return dir
+			}
+		}
+		return ""
+	}
+
+	dirs := map[string]string{}
+	for _, preset := range presets.ConfigurePresets {
+		dir := lookup(preset.Name, 0)
+		if preset.Hidden || dir == "" {
+			continue
+		}
+		dir = strings.ReplaceAll(dir, "${presetName}", preset.Name)
+		dir = strings.TrimPrefix(strings.TrimPrefix(dir, "${sourceDir}"), "/")
+		if dir == "" || strings.ContainsAny(dir, "$ \t") {
+			continue
+		}
+		dirs[preset.Name] = dir
+	}
+	return dirs
+}
+
+// hasCSources checks a directory (non-recursively) for C/C++ sources
+func hasCSources(dir string) bool {
+	files, err := utils.FindFiles(dir, cSourceExts)
+	return err == nil && len(files) > 0
+}
+
+// ============================================================================
+// .NET / HASKELL
+// ============================================================================
+
+// detectDotnetProject detects a .NET solution or project file. The solution
+// (or the only project) becomes the entrypoint handed to the dotnet CLI.
+func (a *Analyzer) detectDotnetProject(path string, result *Result) bool {
+	for _, exts := range [][]string{{".sln"}, {".csproj", ".fsproj"}} {
+		files, err := utils.FindFiles(path, exts)
+		if err != nil || len(files) == 0 {
+			continue
+		}
+
+		result.Language = "dotnet"
+		result.MainEntrypoint = filepath.Base(files[0])
+		result.LanguageFiles = []string{result.MainEntrypoint}
+		a.logger.Debug("Found .NET entrypoint: %s", result.MainEntrypoint)
+		return true
+	}
+	return false
+}
+
+// dotnetProjects lists the root .csproj and .fsproj files and the projects
+// the solutions reference, which usually sit in their own directories
+func dotnetProjects(path string) []string {
+	projects, _ := utils.FindFiles(path, []string{".csproj", ".fsproj"})
+	solutions, _ := utils.FindFiles(path, []string{".sln"})
+	for _, solution := range solutions {
+		content, err := readFile(solution)
+		if err != nil {
+			continue
+		}
+		for _, m := range slnProjectRe.FindAllStringSubmatch(content, -1) {
+			// Solutions store paths with backslashes
+			project := filepath.Join(path, filepath.FromSlash(strings.ReplaceAll(m[1], "\\", "/")))
+			if !slices.Contains(projects, project) {
+				projects = append(projects, project)
+			}
+		}
+	}
+	return projects
+}
+
+// detectHaskellBuild detects a Stack or Cabal project
+func (a *Analyzer) detectHaskellBuild(path string, result *Result) bool {
+	switch {
+	case fileExists(filepath.Join(path, "stack.yaml")):
+		result.BuildTool = "stack"
+		result.LanguageFiles = []string{"stack.yaml"}
+	case fileExists(filepath.Join(path, "cabal.project")):
+		result.BuildTool = "cabal"
+		result.LanguageFiles = []string{"cabal.project"}
+	default:
+		files, err := utils.FindFiles(path, []string{".cabal"})
+		if err != nil || len(files) == 0 {
+			return false
+		}
+		result.BuildTool = "cabal"
+		result.LanguageFiles = []string{filepath.Base(files[0])}
+	}
+
+	result.Language = "haskell"
+	a.logger.Debug("Haskell build tool: %s", result.BuildTool)
+	return true
+}
+
+// ============================================================================
+// GO
+// ============================================================================
+
+var goPackageMainRe = regexp.MustCompile(`(?m)^package\s+main\s*$`)
+
+// goModulePath reads the module path declared in go.mod
+func goModulePath(goModPath string) string {
+	content, err := readFile(goModPath)
+	if err != nil {
+		return ""
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
+
+// goWorkModules lists the module directories a go.work file uses
+func (a *Analyzer) goWorkModules(path string) []string {
+	content, err := readFile(filepath.Join(path, "go.work"))
+	if err != nil {
+		return nil
+	}
+
+	var modules []string
+	inUse := false
+	for _, line := range strings.Split(content, "\n") {
+		line, _, _ = strings.Cut(line, "//")
+		fields := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(line))
+
+		switch {
+		case len(fields) == 0:
+			continue
+		case inUse && fields[0] == ")":
+			inUse = false
+		case inUse:
+			modules = append(modules, strings.Trim(fields[0], `"`))
+		case fields[0] == "use" && len(fields) > 1 && fields[1] == "(":
+			inUse = true
+		case fields[0] == "use" && len(fields) > 1:
+			modules = append(modules, strings.Trim(fields[1], `"`))
+		}
+	}
+
+	a.logger.Debug("go.work uses %d modules: %v", len(modules), modules)
+	return modules
+}
+
+// findGoBinaries lists the main packages of a Go module: the root package
+// and every cmd/<name> directory
+func findGoBinaries(path string) []string {
+	var binaries []string
+
+	if isGoMainPackage(path) {
+		binaries = append(binaries, ".")
+	}
+
+	entries, err := os.ReadDir(filepath.Join(path, "cmd"))
+	if err != nil {
+		return binaries
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && isGoMainPackage(filepath.Join(path, "cmd", entry.Name())) {
+			binaries = append(binaries, "cmd/"+entry.Name())
+		}
+	}
+
+	return binaries
+}
+
+// isGoMainPackage checks whether a directory holds a `package main`
+func isGoMainPackage(dir string) bool {
+	files, err := utils.FindFiles(dir, []string{".go"})
+	if err != nil {
+		return false
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		if content, err := readFile(file); err == nil && goPackageMainRe.MatchString(content) {
+			return true
+		}
+	}
+	return false
+}
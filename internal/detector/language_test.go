@@ -1,7 +1,205 @@
 package detector
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
 
-func TestLanguageDetection(t *testing.T) {
-	t.Skip("Implementation pending")
+	"github.com/gaoubak/Makegen/internal/utils"
+)
+
+// writeFiles creates the given files (relative path -> content) under dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func analyze(t *testing.T, files map[string]string) *Result {
+	t.Helper()
+	dir := t.TempDir()
+	writeFiles(t, dir, files)
+
+	result, err := NewAnalyzer(utils.NewLogger(false)).Analyze(dir)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	return result
+}
+
+func TestDetectMavenMultiModule(t *testing.T) {
+	result := analyze(t, map[string]string{
+		"pom.xml": `<project>
+  <modules>
+    <module>api</module>
+    <module>core</module>
+  </modules>
+  <parent><artifactId>spring-boot-starter-parent</artifactId></parent>
+</project>`,
+		"mvnw": "#!/bin/sh\n",
+	})
+
+	if result.Language != "java" || result.BuildTool != "maven" || !result.HasWrapper {
+		t.Errorf("got language=%q tool=%q wrapper=%v", result.Language, result.BuildTool, result.HasWrapper)
+	}
+	if want := []string{"api", "core"}; !reflect.DeepEqual(result.Modules, want) {
+		t.Errorf("Modules = %v, want %v", result.Modules, want)
+	}
+	if len(result.Frameworks) != 1 || result.Frameworks[0].Name != "Spring Boot" {
+		t.Errorf("Frameworks = %+v, want Spring Boot", result.Frameworks)
+	}
+}
+
+func TestDetectGradleKotlin(t *testing.T) {
+	result := analyze(t, map[string]string{
+		"build.gradle.kts": `plugins {
+    kotlin("jvm") version "1.9.24"
+    id("org.springframework.boot") version "3.3.0"
+}`,
+		"settings.gradle.kts":     `include(":app", ":libs:core")`,
+		"gradlew":                 "#!/bin/sh\n",
+		"src/main/kotlin/Main.kt": "fun main() {}\n",
+	})
+
+	if result.Language != "kotlin" || result.BuildTool != "gradle" || !result.HasWrapper {
+		t.Errorf("got language=%q tool=%q wrapper=%v", result.Language, result.BuildTool, result.HasWrapper)
+	}
+	if want := []string{"app", "libs:core"}; !reflect.DeepEqual(result.Modules, want) {
+		t.Errorf("Modules = %v, want %v", result.Modules, want)
+	}
+	if result.MainEntrypoint != "src/main/kotlin" {
+		t.Errorf("MainEntrypoint = %q", result.MainEntrypoint)
+	}
+	if len(result.Frameworks) != 1 || result.Frameworks[0].Name != "Spring Boot" {
+		t.Errorf("Frameworks = %+v, want Spring Boot", result.Frameworks)
+	}
+}
+
+func TestDetectCRequiresEvidence(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		language string
+		tool     string
+	}{
+		{name: "makefile only", files: map[string]string{"Makefile": "all:\n"}, language: "unknown"},
+		{name: "cmake", files: map[string]string{"CMakeLists.txt": "project(x)\n"}, language: "cpp", tool: "cmake"},
+		{name: "meson", files: map[string]string{"meson.build": "project('x', 'c')\n"}, language: "cpp", tool: "meson"},
+		{name: "autotools", files: map[string]string{"configure.ac": "AC_INIT\n"}, language: "cpp", tool: "autotools"},
+		{name: "sources", files: map[string]string{"Makefile": "all:\n", "src/main.c": "int main(){}\n"}, language: "cpp"},
+		{name: "python with makefile", files: map[string]string{"Makefile": "all:\n", "requirements.txt": "flask\n"}, language: "python"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := analyze(t, tt.files)
+			if result.Language != tt.language || result.BuildTool != tt.tool {
+				t.Errorf("got language=%q tool=%q, want %q %q", result.Language, result.BuildTool, tt.language, tt.tool)
+			}
+			if _, ok := tt.files["Makefile"]; ok != result.HasMakefile {
+				t.Errorf("HasMakefile = %v, want %v", result.HasMakefile, ok)
+			}
+		})
+	}
+}
+
+func TestDetectCMakePresets(t *testing.T) {
+	result := analyze(t, map[string]string{
+		"CMakeLists.txt": "project(x)\n",
+		"CMakePresets.json": `{"version": 3, "configurePresets": [
+			{"name": "base", "hidden": true, "binaryDir": "${sourceDir}/out/${presetName}"},
+			{"name": "debug", "inherits": "base"},
+			{"name": "release", "inherits": ["base"], "binaryDir": "${sourceDir}/build-release"},
+			{"name": "ci", "binaryDir": "$env{BUILD_ROOT}/ci"}
+		]}`,
+	})
+
+	want := map[string]string{"debug": "out/debug", "release": "build-release"}
+	if !reflect.DeepEqual(result.BuildPresets, want) {
+		t.Errorf("BuildPresets = %v, want %v", result.BuildPresets, want)
+	}
+}
+
+func TestDetectAdditionalEcosystems(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		language   string
+		tool       string
+		entrypoint string
+		framework  string
+	}{
+		{name: "phoenix", files: map[string]string{"mix.exs": `{:phoenix, "~> 1.7"}`}, language: "elixir", framework: "Phoenix"},
+		{name: "dotnet solution", files: map[string]string{"Shop.sln": "", "Web/Web.csproj": ""}, language: "dotnet", entrypoint: "Shop.sln"},
+		{name: "aspnet in solution", files: map[string]string{
+			"Shop.sln":           "Project(\"{9A19103F-16F7-4668-BE54-9A1E7A4F7556}\") = \"Web\", \"src\\Web\\Web.csproj\", \"{5E5C3C3B-0F4A-4B43-9A55-0D0C6F7E1A11}\"\r\nEndProject\r\n",
+			"src/Web/Web.csproj": `<Project Sdk="Microsoft.NET.Sdk.Web">`,
+		}, language: "dotnet", entrypoint: "Shop.sln", framework: "ASP.NET Core"},
+		{name: "aspnet", files: map[string]string{"Api.csproj": `<Project Sdk="Microsoft.NET.Sdk.Web">`}, language: "dotnet", entrypoint: "Api.csproj", framework: "ASP.NET Core"},
+		{name: "flutter", files: map[string]string{"pubspec.yaml": "dependencies:\n  flutter:\n    sdk: flutter\n"}, language: "dart", framework: "Flutter"},
+		{name: "deno over node", files: map[string]string{"deno.json": "{}", "package.json": "{}", "main.ts": ""}, language: "deno", entrypoint: "main.ts"},
+		{name: "zig", files: map[string]string{"build.zig": ""}, language: "zig"},
+		{name: "vapor", files: map[string]string{"Package.swift": `.package(url: "https://github.com/vapor/vapor.git", from: "4.0.0")`}, language: "swift", framework: "Vapor"},
+		{name: "stack", files: map[string]string{"stack.yaml": "", "app.cabal": ""}, language: "haskell", tool: "stack"},
+		{name: "cabal", files: map[string]string{"app.cabal": ""}, language: "haskell", tool: "cabal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := analyze(t, tt.files)
+			if result.Language != tt.language || result.BuildTool != tt.tool || result.MainEntrypoint != tt.entrypoint {
+				t.Errorf("got language=%q tool=%q entrypoint=%q", result.Language, result.BuildTool, result.MainEntrypoint)
+			}
+
+			framework := ""
+			if len(result.Frameworks) > 0 {
+				framework = result.Frameworks[0].Name
+			}
+			if framework != tt.framework {
+				t.Errorf("framework = %q, want %q", framework, tt.framework)
+			}
+		})
+	}
+}
+
+func TestDetectGoBinaries(t *testing.T) {
+	result := analyze(t, map[string]string{
+		"go.mod":               "module github.com/acme/tools\n\ngo 1.21\n",
+		"cmd/api/main.go":      "package main\n\nfunc main() {}\n",
+		"cmd/worker/worker.go": "// Worker entrypoint\npackage main\n",
+		"cmd/shared/shared.go": "package shared\n",
+		"internal/lib/lib.go":  "package lib\n",
+		"cmd/api/main_test.go": "package main\n",
+	})
+
+	if result.ModulePath != "github.com/acme/tools" {
+		t.Errorf("ModulePath = %q", result.ModulePath)
+	}
+	if want := []string{"cmd/api", "cmd/worker"}; !reflect.DeepEqual(result.Binaries, want) {
+		t.Errorf("Binaries = %v, want %v", result.Binaries, want)
+	}
+	if result.MainEntrypoint != "cmd/api" {
+		t.Errorf("MainEntrypoint = %q", result.MainEntrypoint)
+	}
+}
+
+func TestDetectGoWorkspace(t *testing.T) {
+	result := analyze(t, map[string]string{
+		"go.work": "go 1.22\n\nuse (\n\t./api // HTTP service\n\t\"./lib\"\n)\n\nuse ./tools\n",
+	})
+
+	if result.Language != "go" {
+		t.Fatalf("Language = %q, want go", result.Language)
+	}
+	if want := []string{"./api", "./lib", "./tools"}; !reflect.DeepEqual(result.Modules, want) {
+		t.Errorf("Modules = %v, want %v", result.Modules, want)
+	}
 }
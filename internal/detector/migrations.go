@@ -0,0 +1,97 @@
+package detector
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// databaseServiceNames are compose service names taken to be the database
+var databaseServiceNames = []string{"postgres", "postgresql", "pg", "db", "database", "mysql", "mariadb"}
+
+// ============================================================================
+// MIGRATION TOOL DETECTION
+// ============================================================================
+
+// findMigrations detects the database migration tool, the directory holding
+// migrations and the compose service running the database
+func (a *Analyzer) findMigrations(path string, result *Result) {
+	tool, dir := a.detectMigrationTool(path, result)
+	if tool == "" {
+		return
+	}
+
+	result.MigrationTool = tool
+	result.MigrationDir = dir
+	a.logger.Debug("Found migration tool: %s (%s)", tool, dir)
+
+	for _, service := range result.DockerServices {
+		for _, name := range databaseServiceNames {
+			if strings.EqualFold(service, name) {
+				result.DatabaseService = service
+				a.logger.Debug("Database compose service: %s", service)
+				return
+			}
+		}
+	}
+}
+
+func (a *Analyzer) detectMigrationTool(path string, result *Result) (string, string) {
+	exists := func(rel string) bool {
+		return fileExists(filepath.Join(path, filepath.FromSlash(rel)))
+	}
+
+	switch {
+	case exists("alembic.ini"):
+		return "alembic", "alembic"
+	case exists("prisma/schema.prisma"):
+		return "prisma", "prisma/migrations"
+	case exists("schema.prisma"):
+		return "prisma", "migrations"
+	case exists("Gemfile") && dirExists(filepath.Join(path, "db", "migrate")):
+		return "rails", "db/migrate"
+	case exists("manage.py") && hasFramework(result, "Django"):
+		return "django", ""
+	case exists("flyway.conf") || exists("flyway.toml"):
+		return "flyway", "sql"
+	case dirExists(filepath.Join(path, "src", "main", "resources", "db", "migration")):
+		return "flyway", "src/main/resources/db/migration"
+	case exists("liquibase.properties"):
+		return "liquibase", "db/changelog"
+	}
+
+	if result.Language != "go" {
+		return "", ""
+	}
+
+	for _, dir := range []string{"migrations", "db/migrations", "sql/migrations"} {
+		files, _ := filepath.Glob(filepath.Join(path, filepath.FromSlash(dir), "*.sql"))
+		if len(files) == 0 {
+			continue
+		}
+
+		goMod, _ := readFile(filepath.Join(path, "go.mod"))
+		if hasContent(goMod, "pressly/goose") {
+			return "goose", dir
+		}
+		for _, file := range files {
+			if content, err := readFile(file); err == nil && strings.Contains(content, "+goose Up") {
+				return "goose", dir
+			}
+			if strings.HasSuffix(file, ".up.sql") {
+				return "golang-migrate", dir
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// hasFramework reports whether a framework was detected
+func hasFramework(result *Result, name string) bool {
+	for _, fw := range result.Frameworks {
+		if fw.Name == name {
+			return true
+		}
+	}
+	return false
+}
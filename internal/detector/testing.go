@@ -0,0 +1,147 @@
+package detector
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	goIntegrationTagRe = regexp.MustCompile(`(?m)^//go:build\s.*\bintegration\b`)
+	goBenchmarkRe      = regexp.MustCompile(`(?m)^func Benchmark\w*\(`)
+)
+
+// ============================================================================
+// TEST FRAMEWORK DETECTION
+// ============================================================================
+
+// findTestFrameworks detects the unit test runner, an end-to-end test runner,
+// whether integration tests are kept apart (Go build tag or pytest marker
+// named "integration", Cargo tests/ directory) and whether benchmarks exist
+func (a *Analyzer) findTestFrameworks(path string, result *Result) {
+	switch result.Language {
+	case "go":
+		result.TestFramework = "go test"
+		walkSources(path, func(file string) {
+			if !strings.HasSuffix(file, "_test.go") {
+				return
+			}
+			// Go tests sit next to the package, not in a test directory
+			result.TestDirFound = true
+			content, err := readFile(file)
+			if err != nil {
+				return
+			}
+			result.IntegrationTests = result.IntegrationTests || goIntegrationTagRe.MatchString(content)
+			if goBenchmarkRe.MatchString(content) {
+				result.HasBenchmarks = true
+				if rel, err := filepath.Rel(path, filepath.Dir(file)); err == nil {
+					pkg := "./" + filepath.ToSlash(rel)
+					if rel == "." {
+						pkg = "."
+					}
+					if !containsString(result.BenchPackages, pkg) {
+						result.BenchPackages = append(result.BenchPackages, pkg)
+					}
+				}
+			}
+		})
+	case "javascript", "typescript":
+		a.detectJSTestFrameworks(path, result)
+	case "python":
+		a.detectPythonTestFramework(path, result)
+	case "ruby":
+		switch {
+		case fileExists(filepath.Join(path, ".rspec")) || dirExists(filepath.Join(path, "spec")):
+			result.TestFramework = "rspec"
+		case dirExists(filepath.Join(path, "test")):
+			result.TestFramework = "minitest"
+		}
+	case "rust":
+		result.TestFramework = "cargo test"
+		result.IntegrationTests = dirExists(filepath.Join(path, "tests"))
+		result.HasBenchmarks = dirExists(filepath.Join(path, "benches"))
+		if fileExists(filepath.Join(path, ".config", "nextest.toml")) {
+			result.TestFramework = "cargo-nextest"
+		}
+	case "java", "kotlin":
+		for _, name := range []string{"pom.xml", "build.gradle", "build.gradle.kts"} {
+			if content, err := readFile(filepath.Join(path, name)); err == nil && hasContent(content, "junit") {
+				result.TestFramework = "junit"
+				break
+			}
+		}
+	}
+
+	if result.TestFramework != "" {
+		a.logger.Debug("Test framework: %s (integration: %v, e2e: %q)", result.TestFramework, result.IntegrationTests, result.E2EFramework)
+	}
+}
+
+// detectJSTestFrameworks picks the unit runner from config files or
+// package.json, preferring vitest over jest over mocha
+func (a *Analyzer) detectJSTestFrameworks(path string, result *Result) {
+	pkg, _ := readFile(filepath.Join(path, "package.json"))
+
+	hasConfig := func(prefixes ...string) bool {
+		for _, prefix := range prefixes {
+			matches, _ := filepath.Glob(filepath.Join(path, prefix+"*"))
+			if len(matches) > 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case hasConfig("vitest.config.") || strings.Contains(pkg, `"vitest"`):
+		result.TestFramework = "vitest"
+		walkSources(path, func(file string) {
+			result.HasBenchmarks = result.HasBenchmarks || strings.Contains(filepath.Base(file), ".bench.")
+		})
+	case hasConfig("jest.config.") || strings.Contains(pkg, `"jest"`):
+		result.TestFramework = "jest"
+	case hasConfig(".mocharc") || strings.Contains(pkg, `"mocha"`):
+		result.TestFramework = "mocha"
+	}
+
+	switch {
+	case hasConfig("playwright.config.") || strings.Contains(pkg, `"@playwright/test"`):
+		result.E2EFramework = "playwright"
+	case hasConfig("cypress.config.", "cypress.json") || strings.Contains(pkg, `"cypress"`):
+		result.E2EFramework = "cypress"
+	}
+}
+
+// detectPythonTestFramework tells pytest from unittest. pytest is chosen
+// when configured or listed as a dependency.
+func (a *Analyzer) detectPythonTestFramework(path string, result *Result) {
+	var configs []string
+	for _, name := range []string{"pytest.ini", "pyproject.toml", "setup.cfg", "tox.ini"} {
+		if content, err := readFile(filepath.Join(path, name)); err == nil {
+			configs = append(configs, content)
+		}
+	}
+	all := strings.Join(configs, "\n")
+
+	pytest := fileExists(filepath.Join(path, "pytest.ini")) ||
+		fileExists(filepath.Join(path, "conftest.py")) ||
+		fileExists(filepath.Join(path, "tests", "conftest.py")) ||
+		hasContent(all, "pytest")
+
+	for _, name := range []string{"requirements.txt", "requirements-dev.txt", "requirements/dev.txt"} {
+		if content, err := readFile(filepath.Join(path, name)); err == nil && hasContent(content, "pytest") {
+			pytest = true
+			result.HasBenchmarks = result.HasBenchmarks || hasContent(content, "pytest-benchmark")
+		}
+	}
+
+	switch {
+	case pytest:
+		result.TestFramework = "pytest"
+		result.HasBenchmarks = result.HasBenchmarks || hasContent(all, "pytest-benchmark")
+		result.IntegrationTests = strings.Contains(all, "markers") && strings.Contains(all, "integration")
+	case result.TestDirFound:
+		result.TestFramework = "unittest"
+	}
+}
@@ -0,0 +1,105 @@
+package detector
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// lintToolRule selects a linter or formatter when one of its config files
+// was found, or when pyproject.toml carries its [tool.<name>] table
+type lintToolRule struct {
+	tool      string
+	format    bool // formatter rather than linter
+	languages []string
+	files     []string
+	pyproject string
+	always    bool // ships with the toolchain, no config needed
+}
+
+var lintToolRules = []lintToolRule{
+	{tool: "golangci-lint", languages: []string{"go"}, files: []string{".golangci.yml", ".golangci.yaml", ".golangci.toml", ".golangci.json"}},
+	{tool: "gofmt", format: true, languages: []string{"go"}, always: true},
+	{tool: "eslint", languages: []string{"javascript", "typescript"}, files: []string{".eslintrc", ".eslintrc.js", ".eslintrc.cjs", ".eslintrc.json", ".eslintrc.yml", ".eslintrc.yaml", "eslint.config.js", "eslint.config.mjs", "eslint.config.cjs", "eslint.config.ts"}},
+	{tool: "prettier", format: true, languages: []string{"javascript", "typescript"}, files: []string{".prettierrc", ".prettierrc.json", ".prettierrc.yml", ".prettierrc.yaml", ".prettierrc.js", ".prettierrc.cjs", "prettier.config.js", "prettier.config.mjs"}},
+	{tool: "ruff", languages: []string{"python"}, files: []string{"ruff.toml", ".ruff.toml"}, pyproject: "ruff"},
+	{tool: "ruff-format", format: true, languages: []string{"python"}, files: []string{"ruff.toml", ".ruff.toml"}, pyproject: "ruff"},
+	{tool: "black", format: true, languages: []string{"python"}, pyproject: "black"},
+	{tool: "pylint", languages: []string{"python"}, files: []string{".pylintrc", "pylintrc"}, pyproject: "pylint"},
+	{tool: "mypy", languages: []string{"python"}, files: []string{"mypy.ini", ".mypy.ini"}, pyproject: "mypy"},
+	{tool: "clippy", languages: []string{"rust"}, always: true},
+	{tool: "rustfmt", format: true, languages: []string{"rust"}, always: true},
+	{tool: "rubocop", languages: []string{"ruby"}, files: []string{".rubocop.yml"}},
+	{tool: "php-cs-fixer", format: true, languages: []string{"php"}, files: []string{".php-cs-fixer.php", ".php-cs-fixer.dist.php"}},
+}
+
+var pyprojectToolRe = regexp.MustCompile(`(?m)^\[tool\.([A-Za-z0-9_-]+)`)
+
+// golangciV2Re matches the top-level version key golangci-lint v2 requires,
+// in YAML, TOML or JSON
+var golangciV2Re = regexp.MustCompile(`(?m)^\s*"?version"?\s*[:=]\s*["']?2["']?\s*,?\s*$`)
+
+// ============================================================================
+// LINTER AND FORMATTER DETECTION
+// ============================================================================
+
+// findLintTools picks linters and formatters from the config files found by
+// findConfigFiles and the [tool.*] tables of pyproject.toml
+func (a *Analyzer) findLintTools(path string, result *Result) {
+	configs := make(map[string]bool)
+	for _, file := range result.ConfigFiles {
+		configs[file] = true
+	}
+
+	if content, err := readFile(filepath.Join(path, "pyproject.toml")); err == nil {
+		for _, m := range pyprojectToolRe.FindAllStringSubmatch(content, -1) {
+			configs["pyproject:"+strings.ToLower(m[1])] = true
+		}
+	}
+
+	for _, rule := range lintToolRules {
+		if !containsString(rule.languages, result.Language) {
+			continue
+		}
+
+		selected := rule.always || (rule.pyproject != "" && configs["pyproject:"+rule.pyproject])
+		for _, file := range rule.files {
+			selected = selected || configs[file]
+		}
+		if !selected {
+			continue
+		}
+
+		if rule.format {
+			result.FormatTools = append(result.FormatTools, rule.tool)
+		} else {
+			result.LintTools = append(result.LintTools, rule.tool)
+		}
+		a.logger.Debug("Selected tool: %s", rule.tool)
+
+		if rule.tool == "golangci-lint" {
+			result.GolangciLintV2 = golangciConfigV2(path, rule.files)
+		}
+	}
+}
+
+// golangciConfigV2 reports whether the golangci config found is in the v2
+// format, which v1 cannot read and the other way round
+func golangciConfigV2(path string, files []string) bool {
+	for _, file := range files {
+		if content, err := readFile(filepath.Join(path, file)); err == nil {
+			return golangciV2Re.MatchString(content)
+		}
+	}
+	return false
+}
+
+// containsString checks if a slice contains a string
+func containsString(slice []string, s string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
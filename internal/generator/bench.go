@@ -0,0 +1,134 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/config"
+)
+
+// benchCommands holds the recipes of the benchmark targets for one runner.
+// bench-baseline saves a run under BENCH_DIR that bench-compare measures
+// against.
+type benchCommands struct {
+	bench    []string
+	baseline []string
+	compare  []string
+}
+
+// goBench runs only benchmarks: -run '^$' matches no test
+const goBench = "$(GO) test -run '^$$' -bench '$(BENCH)' -benchmem -count $(COUNT)"
+
+// benchRunnerCommands returns the benchmark recipes for the test framework
+func benchRunnerCommands(cfg *config.MakefileConfig) (benchCommands, bool) {
+	switch cfg.TestFramework {
+	case "go test":
+		return benchCommands{
+			bench: []string{goRecipeLine(cfg, goBench+" ./...")},
+			baseline: []string{
+				"@mkdir -p $(BENCH_DIR)",
+				"@$(MAKE) -s --no-print-directory bench > $(BENCH_DIR)/baseline.txt",
+				`@echo "Saved baseline to $(BENCH_DIR)/baseline.txt"`,
+			},
+			compare: []string{
+				`@test -f $(BENCH_DIR)/baseline.txt || { echo "No baseline: run 'make bench-baseline' first"; exit 1; }`,
+				"@$(MAKE) -s --no-print-directory bench > $(BENCH_DIR)/new.txt",
+				"@if command -v benchstat >/dev/null 2>&1; then \\\n" +
+					"\t\tbenchstat $(BENCH_DIR)/baseline.txt $(BENCH_DIR)/new.txt; \\\n" +
+					"\telse \\\n" +
+					"\t\t$(GO) run golang.org/x/perf/cmd/benchstat@latest $(BENCH_DIR)/baseline.txt $(BENCH_DIR)/new.txt; \\\n" +
+					"\tfi",
+			},
+		}, true
+	case "pytest":
+		pytest := "$(PYTHON) -m pytest --benchmark-only --benchmark-storage=$(BENCH_DIR) --benchmark-min-rounds=$(COUNT) $(if $(BENCH),-k '$(BENCH)')"
+		return benchCommands{
+			bench:    []string{pytest},
+			baseline: []string{pytest + " --benchmark-save=baseline"},
+			compare:  []string{pytest + " --benchmark-compare"},
+		}, true
+	case "vitest":
+		vitest := "npx vitest bench --run $(BENCH)"
+		return benchCommands{
+			bench:    []string{vitest},
+			baseline: []string{"@mkdir -p $(BENCH_DIR)", vitest + " --outputJson $(BENCH_DIR)/baseline.json"},
+			compare:  []string{vitest + " --compare $(BENCH_DIR)/baseline.json"},
+		}, true
+	case "cargo test", "cargo-nextest":
+		// Baselines are Criterion's; plain libtest benches only support bench
+		return benchCommands{
+			bench:    []string{"cargo bench $(BENCH)"},
+			baseline: []string{"cargo bench $(BENCH) -- --save-baseline baseline"},
+			compare:  []string{"cargo bench $(BENCH) -- --baseline baseline"},
+		}, true
+	}
+
+	return benchCommands{}, false
+}
+
+func (b *Builder) writeBenchVariables(w *strings.Builder, cfg *config.MakefileConfig) {
+	if _, ok := benchRunnerCommands(cfg); !ok {
+		return
+	}
+
+	// BENCH selects benchmarks: a regexp for Go, a filter elsewhere. Only
+	// go test and pytest-benchmark take a run count.
+	switch cfg.TestFramework {
+	case "go test":
+		fmt.Fprintf(w, "BENCH ?= .\n")
+		fmt.Fprintf(w, "COUNT ?= 6\n")
+		// profile-* profile one package, the first with benchmarks by default
+		pkg := "."
+		if len(cfg.BenchPackages) > 0 {
+			pkg = cfg.BenchPackages[0]
+		}
+		fmt.Fprintf(w, "PKG ?= %s\n", pkg)
+	case "pytest":
+		fmt.Fprintf(w, "BENCH ?=\n")
+		fmt.Fprintf(w, "COUNT ?= 5\n")
+	default:
+		fmt.Fprintf(w, "BENCH ?=\n")
+	}
+	fmt.Fprintf(w, "BENCH_DIR ?= .bench\n")
+}
+
+func (b *Builder) writeBenchTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	commands, ok := benchRunnerCommands(cfg)
+	if !ok {
+		return
+	}
+
+	fmt.Fprintf(w, "# Benchmark Targets\n")
+
+	targets := []struct {
+		name  string
+		lines []string
+	}{
+		{"bench", commands.bench},
+		{"bench-baseline", commands.baseline},
+		{"bench-compare", commands.compare},
+	}
+	for _, target := range targets {
+		fmt.Fprintf(w, "%s:\n", target.name)
+		for _, line := range target.lines {
+			fmt.Fprintf(w, "\t%s\n", line)
+		}
+		fmt.Fprintf(w, ".PHONY: %s\n\n", target.name)
+	}
+
+	if cfg.TestFramework == "go test" {
+		b.writeGoProfileTargets(w)
+	}
+}
+
+// writeGoProfileTargets profiles the benchmarks of one package (PKG): the
+// go tool only writes profiles for a single package
+func (b *Builder) writeGoProfileTargets(w *strings.Builder) {
+	for _, profile := range []string{"cpu", "mem"} {
+		fmt.Fprintf(w, "profile-%s:\n", profile)
+		fmt.Fprintf(w, "\t@mkdir -p $(BENCH_DIR)\n")
+		fmt.Fprintf(w, "\t$(GO) test -run '^$$' -bench '$(BENCH)' -benchmem -o $(BENCH_DIR)/bench.test -%sprofile $(BENCH_DIR)/%s.out $(PKG)\n", profile, profile)
+		fmt.Fprintf(w, "\t@echo \"Inspect with: $(GO) tool pprof -http=: $(BENCH_DIR)/bench.test $(BENCH_DIR)/%s.out\"\n", profile)
+		fmt.Fprintf(w, ".PHONY: profile-%s\n\n", profile)
+	}
+}
@@ -2,6 +2,7 @@ package generator
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/gaoubak/Makegen/internal/config"
@@ -31,14 +32,34 @@ func (b *Builder) Build(cfg *config.MakefileConfig) (string, error) {
 	b.writeVariables(&content, cfg)
 
 	// Help target
-	b.writeHelpTarget(&content)
+	b.writeHelpTarget(&content, cfg)
+
+	// Tool bootstrap targets
+	if cfg.PinTools {
+		b.writeToolsTargets(&content, cfg)
+	}
+
+	// Code generation targets
+	if len(cfg.Generators) > 0 {
+		b.writeGenerateTargets(&content, cfg)
+	}
 
 	// Build targets
 	b.writeBuildTargets(&content, cfg)
 
+	// Database targets
+	if cfg.MigrationTool != "" {
+		b.writeDatabaseTargets(&content, cfg)
+	}
+
 	// Test targets
 	b.writeTestTargets(&content, cfg)
 
+	// Benchmark targets
+	if cfg.EnableBench {
+		b.writeBenchTargets(&content, cfg)
+	}
+
 	// Lint targets
 	b.writeLintTargets(&content, cfg)
 
@@ -50,6 +71,11 @@ func (b *Builder) Build(cfg *config.MakefileConfig) (string, error) {
 		b.writeDockerTargets(&content, cfg)
 	}
 
+	// Security targets
+	if cfg.EnableSecurity {
+		b.writeSecurityTargets(&content, cfg)
+	}
+
 	// CI/CD targets
 	if cfg.EnableCI {
 		b.writeCITargets(&content)
@@ -63,11 +89,19 @@ func (b *Builder) Build(cfg *config.MakefileConfig) (string, error) {
 	// Custom targets
 	b.writeCustomTargets(&content, cfg)
 
+	// Doctor reads the recipes written above
+	b.writeDoctorTargets(&content, cfg)
+
 	return content.String(), nil
 }
 
 func (b *Builder) writeHeader(w *strings.Builder, cfg *config.MakefileConfig) {
-	fmt.Fprintf(w, "# Generated Makefile\n")
+	if cfg.Fragment != "" {
+		fmt.Fprintf(w, "# Generated Makefile fragment\n")
+		fmt.Fprintf(w, "# Use it from your Makefile with: include %s\n", cfg.Fragment)
+	} else {
+		fmt.Fprintf(w, "# Generated Makefile\n")
+	}
 	fmt.Fprintf(w, "# Project: %s\n", cfg.ProjectName)
 	fmt.Fprintf(w, "# Language: %s\n", cfg.Language)
 	if cfg.Framework != nil {
@@ -87,12 +121,83 @@ func (b *Builder) writeVariables(w *strings.Builder, cfg *config.MakefileConfig)
 		fmt.Fprintf(w, "GO := go\n")
 		fmt.Fprintf(w, "GOFLAGS := -v\n")
 		fmt.Fprintf(w, "OUT_DIR := bin\n")
+		if cfg.ModulePath != "" {
+			fmt.Fprintf(w, "MODULE := %s\n", cfg.ModulePath)
+		}
+		if len(cfg.Modules) > 0 {
+			fmt.Fprintf(w, "GO_MODULES := %s\n", strings.Join(cfg.Modules, " "))
+		}
+		if cfg.EnableRelease {
+			b.writeGoReleaseVariables(w, cfg)
+		}
 	case "javascript", "typescript":
-		fmt.Fprintf(w, "NPM := npm\n")
+		if cfg.PackageManager != "" {
+			fmt.Fprintf(w, "NPM := %s\n", cfg.PackageManager)
+		} else {
+			fmt.Fprintf(w, "NPM := npm\n")
+		}
 		fmt.Fprintf(w, "NODE := node\n")
 	case "python":
 		fmt.Fprintf(w, "PYTHON := python3\n")
 		fmt.Fprintf(w, "PIP := pip3\n")
+	case "java", "kotlin":
+		b.writeJVMVariables(w, cfg)
+	case "ruby":
+		fmt.Fprintf(w, "BUNDLE := bundle\n")
+		fmt.Fprintf(w, "RAKE := $(BUNDLE) exec rake\n")
+		b.writePortVariable(w, cfg)
+	case "php":
+		fmt.Fprintf(w, "PHP := php\n")
+		fmt.Fprintf(w, "COMPOSER := composer\n")
+		b.writePortVariable(w, cfg)
+	case "cpp":
+		b.writeCVariables(w, cfg)
+	case "elixir":
+		fmt.Fprintf(w, "MIX := mix\n")
+		fmt.Fprintf(w, "export MIX_ENV ?= dev\n")
+	case "dotnet":
+		fmt.Fprintf(w, "DOTNET := dotnet\n")
+		fmt.Fprintf(w, "CONFIGURATION ?= Debug\n")
+		fmt.Fprintf(w, "SOLUTION := %s\n", cfg.Entrypoint)
+	case "dart":
+		b.writeDartVariables(w, cfg)
+	case "deno":
+		fmt.Fprintf(w, "DENO := deno\n")
+	case "zig":
+		fmt.Fprintf(w, "ZIG := zig\n")
+		fmt.Fprintf(w, "OPTIMIZE ?= Debug\n")
+	case "swift":
+		fmt.Fprintf(w, "SWIFT := swift\n")
+		fmt.Fprintf(w, "CONFIGURATION ?= debug\n")
+		if cfg.Framework != nil {
+			b.writePortVariable(w, cfg)
+		}
+	case "haskell":
+		if cfg.BuildTool == "stack" {
+			fmt.Fprintf(w, "STACK := stack\n")
+		} else {
+			fmt.Fprintf(w, "CABAL := cabal\n")
+		}
+	}
+
+	if cfg.EnableCoverage {
+		b.writeCoverageVariables(w, cfg)
+	}
+
+	if cfg.EnableBench {
+		b.writeBenchVariables(w, cfg)
+	}
+
+	if cfg.MigrationTool != "" {
+		b.writeDatabaseVariables(w, cfg)
+	}
+
+	if cfg.EnableSecurity {
+		fmt.Fprintf(w, "SBOM_FILE ?= sbom.cdx.json\n")
+	}
+
+	if cfg.PinTools {
+		b.writeToolsVariables(w, cfg)
 	}
 
 	if cfg.HasDocker {
@@ -103,13 +208,27 @@ func (b *Builder) writeVariables(w *strings.Builder, cfg *config.MakefileConfig)
 	fmt.Fprintf(w, "\n")
 }
 
-func (b *Builder) writeHelpTarget(w *strings.Builder) {
+// writePortVariable exposes the framework's dev server port
+func (b *Builder) writePortVariable(w *strings.Builder, cfg *config.MakefileConfig) {
+	port := 8000
+	if cfg.Framework != nil && cfg.Framework.Port > 0 {
+		port = cfg.Framework.Port
+	}
+	fmt.Fprintf(w, "PORT ?= %d\n", port)
+}
+
+func (b *Builder) writeHelpTarget(w *strings.Builder, cfg *config.MakefileConfig) {
 	fmt.Fprintf(w, ".PHONY: help\n\n")
 	fmt.Fprintf(w, "help:\n")
 	fmt.Fprintf(w, "\t@echo \"$(PROJECT_NAME) - Makefile Targets\"\n")
 	fmt.Fprintf(w, "\t@echo \"Usage: make <target>\"\n")
 	fmt.Fprintf(w, "\t@echo \"\"\n")
-	fmt.Fprintf(w, "\t@grep -E '^[a-zA-Z_-]+:' Makefile | sed 's/:$$//' | awk '{print \"  - \" $$1}'\n")
+	// The require-* guard lines add prerequisites to targets listed already
+	filter := ""
+	if cfg.EnableGuards {
+		filter = " | grep -vE '^[^:]+:( require-[^ ]+)+$$'"
+	}
+	fmt.Fprintf(w, "\t@grep -hE '^[a-zA-Z_-]+:' $(MAKEFILE_LIST)%s | sed 's/:.*$$//' | awk '!seen[$$1]++ {print \"  - \" $$1}'\n", filter)
 	fmt.Fprintf(w, "\t@echo \"\"\n\n")
 }
 
@@ -119,18 +238,7 @@ func (b *Builder) writeBuildTargets(w *strings.Builder, cfg *config.MakefileConf
 	// Language-specific build targets
 	switch cfg.Language {
 	case "go":
-		fmt.Fprintf(w, "build:\n")
-		fmt.Fprintf(w, "\t$(GO) build $(GOFLAGS) -o $(OUT_DIR)/$(PROJECT_NAME) .\n")
-		fmt.Fprintf(w, ".PHONY: build\n\n")
-
-		fmt.Fprintf(w, "clean:\n")
-		fmt.Fprintf(w, "\trm -rf $(OUT_DIR)\n")
-		fmt.Fprintf(w, "\t$(GO) clean\n")
-		fmt.Fprintf(w, ".PHONY: clean\n\n")
-
-		fmt.Fprintf(w, "run: build\n")
-		fmt.Fprintf(w, "\t./$(OUT_DIR)/$(PROJECT_NAME)\n")
-		fmt.Fprintf(w, ".PHONY: run\n\n")
+		b.writeGoBuildTargets(w, cfg)
 
 	case "javascript", "typescript":
 		fmt.Fprintf(w, "install:\n")
@@ -162,58 +270,40 @@ func (b *Builder) writeBuildTargets(w *strings.Builder, cfg *config.MakefileConf
 		fmt.Fprintf(w, "\tfind . -type f -name '*.pyc' -delete\n")
 		fmt.Fprintf(w, "\tfind . -type d -name '__pycache__' -delete\n")
 		fmt.Fprintf(w, ".PHONY: clean\n\n")
-	}
-}
 
-func (b *Builder) writeTestTargets(w *strings.Builder, cfg *config.MakefileConfig) {
-	if cfg.TestFramework == "" {
-		return
-	}
+	case "java", "kotlin":
+		b.writeJVMBuildTargets(w, cfg)
 
-	fmt.Fprintf(w, "# Test Targets\n")
-
-	switch cfg.TestFramework {
-	case "go test":
-		fmt.Fprintf(w, "test:\n")
-		fmt.Fprintf(w, "\t$(GO) test -v ./...\n")
-		fmt.Fprintf(w, ".PHONY: test\n\n")
-	case "jest":
-		fmt.Fprintf(w, "test:\n")
-		fmt.Fprintf(w, "\t$(NPM) test\n")
-		fmt.Fprintf(w, ".PHONY: test\n\n")
-	case "pytest":
-		fmt.Fprintf(w, "test:\n")
-		fmt.Fprintf(w, "\t$(PYTHON) -m pytest\n")
-		fmt.Fprintf(w, ".PHONY: test\n\n")
-	}
-}
+	case "ruby":
+		b.writeRubyBuildTargets(w, cfg)
 
-func (b *Builder) writeLintTargets(w *strings.Builder, cfg *config.MakefileConfig) {
-	if len(cfg.LintTools) == 0 {
-		return
-	}
+	case "php":
+		b.writePHPBuildTargets(w, cfg)
 
-	fmt.Fprintf(w, "# Lint Targets\n")
-	fmt.Fprintf(w, "lint:\n")
+	case "cpp":
+		b.writeCBuildTargets(w, cfg)
 
-	for _, tool := range cfg.LintTools {
-		fmt.Fprintf(w, "\t%s\n", tool)
-	}
-	fmt.Fprintf(w, ".PHONY: lint\n\n")
-}
+	case "elixir":
+		b.writeElixirBuildTargets(w, cfg)
 
-func (b *Builder) writeFormatTargets(w *strings.Builder, cfg *config.MakefileConfig) {
-	if len(cfg.FormatTools) == 0 {
-		return
-	}
+	case "dotnet":
+		b.writeDotnetBuildTargets(w, cfg)
+
+	case "dart":
+		b.writeDartBuildTargets(w, cfg)
+
+	case "deno":
+		b.writeDenoBuildTargets(w, cfg)
+
+	case "zig":
+		b.writeZigBuildTargets(w)
 
-	fmt.Fprintf(w, "# Format Targets\n")
-	fmt.Fprintf(w, "format:\n")
+	case "swift":
+		b.writeSwiftBuildTargets(w, cfg)
 
-	for _, tool := range cfg.FormatTools {
-		fmt.Fprintf(w, "\t%s\n", tool)
+	case "haskell":
+		b.writeHaskellBuildTargets(w, cfg)
 	}
-	fmt.Fprintf(w, ".PHONY: format\n\n")
 }
 
 func (b *Builder) writeDockerTargets(w *strings.Builder, cfg *config.MakefileConfig) {
@@ -271,17 +361,40 @@ func (b *Builder) writeCustomTargets(w *strings.Builder, cfg *config.MakefileCon
 
 	fmt.Fprintf(w, "# Custom Targets\n")
 
-	for _, target := range cfg.CustomTargets {
-		fmt.Fprintf(w, "%s:", target.Name)
-		if len(target.Dependencies) > 0 {
-			fmt.Fprintf(w, " %s", strings.Join(target.Dependencies, " "))
-		}
-		fmt.Fprintf(w, "\n")
+	names := make([]string, 0, len(cfg.CustomTargets))
+	for name := range cfg.CustomTargets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-		for _, cmd := range target.Commands {
-			fmt.Fprintf(w, "\t%s\n", cmd)
-		}
+	for _, name := range names {
+		fmt.Fprintf(w, "%s\n", FormatTarget(cfg.CustomTargets[name]))
+	}
+}
+
+// FormatTarget writes the rule of a custom target: its description as a
+// comment, a check that the guard variable is set, then the commands
+func FormatTarget(target config.Target) string {
+	var w strings.Builder
+	if target.Description != "" {
+		fmt.Fprintf(&w, "# %s\n", target.Description)
+	}
+
+	fmt.Fprintf(&w, "%s:", target.Name)
+	if len(target.Dependencies) > 0 {
+		fmt.Fprintf(&w, " %s", strings.Join(target.Dependencies, " "))
+	}
+	fmt.Fprintf(&w, "\n")
+
+	if target.Guard != "" {
+		fmt.Fprintf(&w, "\t@test -n \"$(%s)\" || { echo \"usage: make $@ %s=<value>\"; exit 1; }\n", target.Guard, target.Guard)
+	}
+	for _, cmd := range target.Commands {
+		fmt.Fprintf(&w, "\t%s\n", cmd)
+	}
 
-		fmt.Fprintf(w, ".PHONY: %s\n\n", target.Name)
+	if target.Phony {
+		fmt.Fprintf(&w, ".PHONY: %s\n", target.Name)
 	}
+	return w.String()
 }
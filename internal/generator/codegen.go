@@ -0,0 +1,155 @@
+package generator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/config"
+)
+
+// generatorCommand returns the command running a code generator, or "" when
+// the generator has no sensible default for the project language
+func generatorCommand(cfg *config.MakefileConfig, gen config.CodeGenerator) string {
+	switch gen.Name {
+	case "go-generate":
+		return "$(GO) generate ./..."
+	case "buf":
+		return "buf generate"
+	case "protoc":
+		switch cfg.Language {
+		case "go":
+			return "protoc --proto_path=. --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative $(PROTO_FILES)"
+		case "python":
+			return "$(PYTHON) -m grpc_tools.protoc --proto_path=. --python_out=. --grpc_python_out=. $(PROTO_FILES)"
+		}
+	case "sqlc":
+		return "sqlc generate -f " + gen.Config
+	case "openapi":
+		switch cfg.Language {
+		case "go":
+			return "oapi-codegen -config oapi-codegen.yaml " + gen.Config
+		case "javascript", "typescript":
+			return "npx openapi-typescript " + gen.Config + " -o src/api/schema.d.ts"
+		}
+	case "gqlgen":
+		return "$(GO) run github.com/99designs/gqlgen generate --config " + gen.Config
+	case "mockery":
+		return "mockery"
+	}
+	return ""
+}
+
+// generatedPaths returns git pathspecs matching the files a generator
+// writes by convention. Generators configured to write elsewhere are
+// covered by overriding GENERATED_PATHS.
+func generatedPaths(cfg *config.MakefileConfig, gen config.CodeGenerator) []string {
+	switch gen.Name {
+	case "go-generate":
+		return []string{"*_string.go", "*_gen.go", "*.gen.go", "*_enumer.go"}
+	case "buf", "protoc":
+		switch cfg.Language {
+		case "python":
+			return []string{"*_pb2.py", "*_pb2_grpc.py", "*_pb2.pyi"}
+		case "javascript", "typescript":
+			return []string{"*_pb.js", "*_pb.ts", "*_pb.d.ts"}
+		}
+		return []string{"*.pb.go"}
+	case "sqlc":
+		return []string{"*.sql.go"}
+	case "openapi":
+		if cfg.Language == "go" {
+			return []string{"*.gen.go"}
+		}
+		return []string{"src/api/schema.d.ts"}
+	case "gqlgen":
+		return []string{"*generated.go", "*models_gen.go"}
+	case "mockery":
+		return []string{"mocks", "*/mocks/*"}
+	}
+	return nil
+}
+
+// compileTargets are the targets whose recipes compile the project, which
+// must wait for generated code
+func compileTargets(cfg *config.MakefileConfig) []string {
+	binaries := goBinaries(cfg)
+	if cfg.Language != "go" || len(binaries) == 0 || (len(binaries) == 1 && binaries[0].pkg == ".") {
+		return []string{"build"}
+	}
+
+	var targets []string
+	for _, bin := range binaries {
+		targets = append(targets, "build-"+bin.name)
+	}
+	return targets
+}
+
+// writeGenerateTargets writes one generate-<name> target per generator, an
+// aggregate generate target that build depends on, and generate-check which
+// fails when committed generated code is stale
+func (b *Builder) writeGenerateTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	var targets []string
+	var paths []string
+	var body strings.Builder
+
+	for _, gen := range cfg.Generators {
+		cmd := generatorCommand(cfg, gen)
+		if cmd == "" {
+			b.logger.Debug("No generate command for %s in a %s project", gen.Name, cfg.Language)
+			continue
+		}
+
+		name := "generate-" + strings.TrimSuffix(gen.Name, "-generate")
+		targets = append(targets, name)
+		for _, path := range generatedPaths(cfg, gen) {
+			if !slices.Contains(paths, "'"+path+"'") {
+				paths = append(paths, "'"+path+"'")
+			}
+		}
+
+		fmt.Fprintf(&body, "%s:\n", name)
+		if gen.Name == "go-generate" {
+			fmt.Fprintf(&body, "%s", goForEachModule(cfg, cmd))
+		} else {
+			fmt.Fprintf(&body, "\t%s\n", cmd)
+		}
+		fmt.Fprintf(&body, ".PHONY: %s\n\n", name)
+	}
+
+	if len(targets) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "# Code Generation Targets\n")
+	for _, gen := range cfg.Generators {
+		if gen.Name == "protoc" {
+			fmt.Fprintf(w, "PROTO_FILES := $(shell find . -name '*.proto' -not -path './vendor/*' -not -path './node_modules/*')\n\n")
+		}
+	}
+
+	fmt.Fprintf(w, "generate: %s\n", strings.Join(targets, " "))
+	fmt.Fprintf(w, ".PHONY: generate\n\n")
+	w.WriteString(body.String())
+
+	// Only generated files are checked, so unrelated edits and untracked
+	// build output such as .bin/ or coverage/ do not fail it
+	fmt.Fprintf(w, "GENERATED_PATHS ?= %s\n\n", strings.Join(paths, " "))
+	fmt.Fprintf(w, "generate-check: generate\n")
+	fmt.Fprintf(w, "\t@git diff --exit-code --stat -- $(GENERATED_PATHS) || { \\\n")
+	fmt.Fprintf(w, "\t\techo \"Generated code is out of date: run 'make generate' and commit the result\"; \\\n")
+	fmt.Fprintf(w, "\t\texit 1; \\\n")
+	fmt.Fprintf(w, "\t}\n")
+	fmt.Fprintf(w, "\t@untracked=\"$$(git ls-files --others --exclude-standard -- $(GENERATED_PATHS))\"; \\\n")
+	fmt.Fprintf(w, "\tif [ -n \"$$untracked\" ]; then \\\n")
+	fmt.Fprintf(w, "\t\techo \"$$untracked\"; \\\n")
+	fmt.Fprintf(w, "\t\techo \"Generated files are not committed: add them to git\"; \\\n")
+	fmt.Fprintf(w, "\t\texit 1; \\\n")
+	fmt.Fprintf(w, "\tfi\n")
+	fmt.Fprintf(w, ".PHONY: generate-check\n\n")
+
+	// Generated code must exist before anything is compiled. Make finishes
+	// every prerequisite of a rule before its recipe, even with -j, so
+	// generate goes on the rules that compile rather than on an aggregate.
+	fmt.Fprintf(w, "%s: generate\n\n", strings.Join(compileTargets(cfg), " "))
+}
@@ -0,0 +1,116 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/config"
+)
+
+// coverageCommands holds the recipes of coverage and coverage-check. When
+// checkReport is set, coverage-check reads the report left by coverage and
+// depends on it; otherwise it runs the suite again with a threshold.
+type coverageCommands struct {
+	coverage    []string
+	check       []string
+	checkReport bool
+}
+
+// goCoverageCheck fails when the total of `go tool cover -func` is below
+// COVERAGE_MIN
+const goCoverageCheck = `@$(GO) tool cover -func=$(COVERAGE_DIR)/coverage.out | awk '/^total:/ { sub("%", "", $$3); ` +
+	`if ($$3 + 0 < $(COVERAGE_MIN)) { printf "coverage %s%% is below $(COVERAGE_MIN)%%\n", $$3; exit 1 } ` +
+	`printf "coverage %s%%\n", $$3 }'`
+
+// coverageRunnerCommands returns the coverage recipes for the test framework
+func coverageRunnerCommands(cfg *config.MakefileConfig) (coverageCommands, bool) {
+	switch cfg.TestFramework {
+	case "go test":
+		if len(cfg.Modules) == 0 {
+			return coverageCommands{
+				coverage: []string{
+					"@mkdir -p $(COVERAGE_DIR)",
+					"$(GO) test -coverprofile=$(COVERAGE_DIR)/coverage.out ./...",
+					"$(GO) tool cover -html=$(COVERAGE_DIR)/coverage.out -o $(COVERAGE_DIR)/coverage.html",
+				},
+				check:       []string{goCoverageCheck},
+				checkReport: true,
+			}, true
+		}
+
+		// Workspaces: one profile per module, merged under a single header
+		profile := "$(CURDIR)/$(COVERAGE_DIR)/$$(echo $$mod | tr -c 'a-zA-Z0-9\\n' _).cov"
+		loop := goRecipeLine(cfg, "$(GO) test -coverprofile="+profile+" ./...")
+		return coverageCommands{
+			coverage: []string{
+				"@mkdir -p $(COVERAGE_DIR) && rm -f $(COVERAGE_DIR)/*.cov",
+				loop,
+				`@{ echo "mode: set"; for f in $(COVERAGE_DIR)/*.cov; do tail -n +2 $$f; done; } > $(COVERAGE_DIR)/coverage.out`,
+				"$(GO) tool cover -html=$(COVERAGE_DIR)/coverage.out -o $(COVERAGE_DIR)/coverage.html",
+			},
+			check:       []string{goCoverageCheck},
+			checkReport: true,
+		}, true
+	case "pytest":
+		return coverageCommands{
+			coverage:    []string{"$(PYTHON) -m pytest --cov --cov-report=term --cov-report=html:$(COVERAGE_DIR)/html --cov-report=xml:$(COVERAGE_DIR)/coverage.xml"},
+			check:       []string{"$(PYTHON) -m coverage report --fail-under=$(COVERAGE_MIN)"},
+			checkReport: true,
+		}, true
+	case "jest":
+		jest := "npx jest --coverage --coverageDirectory=$(COVERAGE_DIR)"
+		return coverageCommands{
+			coverage: []string{jest},
+			check:    []string{jest + ` --coverageThreshold='{"global":{"lines":$(COVERAGE_MIN)}}'`},
+		}, true
+	case "vitest":
+		vitest := "npx vitest run --coverage --coverage.reportsDirectory=$(COVERAGE_DIR)"
+		return coverageCommands{
+			coverage: []string{vitest},
+			check:    []string{vitest + " --coverage.thresholds.lines=$(COVERAGE_MIN)"},
+		}, true
+	case "cargo test", "cargo-nextest":
+		llvmCov := "cargo llvm-cov"
+		if cfg.TestFramework == "cargo-nextest" {
+			llvmCov += " nextest"
+		}
+		return coverageCommands{
+			coverage: []string{llvmCov + " --html --output-dir $(COVERAGE_DIR)"},
+			check:    []string{llvmCov + " --fail-under-lines $(COVERAGE_MIN)"},
+		}, true
+	}
+
+	return coverageCommands{}, false
+}
+
+func (b *Builder) writeCoverageVariables(w *strings.Builder, cfg *config.MakefileConfig) {
+	if _, ok := coverageRunnerCommands(cfg); !ok {
+		return
+	}
+
+	fmt.Fprintf(w, "COVERAGE_DIR ?= coverage\n")
+	fmt.Fprintf(w, "COVERAGE_MIN ?= 80\n")
+}
+
+func (b *Builder) writeCoverageTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	commands, ok := coverageRunnerCommands(cfg)
+	if !ok {
+		return
+	}
+
+	fmt.Fprintf(w, "coverage:\n")
+	for _, line := range commands.coverage {
+		fmt.Fprintf(w, "\t%s\n", line)
+	}
+	fmt.Fprintf(w, ".PHONY: coverage\n\n")
+
+	if commands.checkReport {
+		fmt.Fprintf(w, "coverage-check: coverage\n")
+	} else {
+		fmt.Fprintf(w, "coverage-check:\n")
+	}
+	for _, line := range commands.check {
+		fmt.Fprintf(w, "\t%s\n", line)
+	}
+	fmt.Fprintf(w, ".PHONY: coverage-check\n\n")
+}
@@ -0,0 +1,124 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/config"
+)
+
+func (b *Builder) writeCVariables(w *strings.Builder, cfg *config.MakefileConfig) {
+	switch cfg.BuildTool {
+	case "cmake":
+		fmt.Fprintf(w, "CMAKE := cmake\n")
+		fmt.Fprintf(w, "CTEST := ctest\n")
+		fmt.Fprintf(w, "BUILD_DIR ?= build\n")
+		fmt.Fprintf(w, "BUILD_TYPE ?= Debug\n")
+		fmt.Fprintf(w, "CMAKE_FLAGS ?=\n")
+		fmt.Fprintf(w, "PREFIX ?= /usr/local\n")
+		// Set PRESET=<name> to drive everything from CMakePresets.json
+		fmt.Fprintf(w, "PRESET ?=\n")
+		// clean removes the preset's binaryDir instead of BUILD_DIR
+		presets := make([]string, 0, len(cfg.BuildPresets))
+		for name := range cfg.BuildPresets {
+			presets = append(presets, name)
+		}
+		sort.Strings(presets)
+		for _, name := range presets {
+			fmt.Fprintf(w, "PRESET_DIR_%s := %s\n", name, cfg.BuildPresets[name])
+		}
+		fmt.Fprintf(w, "PRESET_BUILD_DIR ?= $(or $(PRESET_DIR_$(PRESET)),$(error no binaryDir known for preset $(PRESET), set PRESET_BUILD_DIR))\n")
+	case "meson":
+		fmt.Fprintf(w, "MESON := meson\n")
+		fmt.Fprintf(w, "BUILD_DIR ?= build\n")
+		fmt.Fprintf(w, "BUILD_TYPE ?= debug\n")
+		fmt.Fprintf(w, "PREFIX ?= /usr/local\n")
+	case "autotools":
+		fmt.Fprintf(w, "BUILD_DIR ?= build\n")
+		fmt.Fprintf(w, "PREFIX ?= /usr/local\n")
+		fmt.Fprintf(w, "CONFIGURE_FLAGS ?=\n")
+	}
+}
+
+func (b *Builder) writeCBuildTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	switch cfg.BuildTool {
+	case "cmake":
+		b.writeCMakeBuildTargets(w)
+	case "meson":
+		b.writeMesonBuildTargets(w)
+	case "autotools":
+		b.writeAutotoolsBuildTargets(w)
+	}
+}
+
+func (b *Builder) writeCMakeBuildTargets(w *strings.Builder) {
+	fmt.Fprintf(w, "configure:\n")
+	fmt.Fprintf(w, "\t$(CMAKE) $(if $(PRESET),--preset $(PRESET),-S . -B $(BUILD_DIR) -DCMAKE_BUILD_TYPE=$(BUILD_TYPE)) $(CMAKE_FLAGS)\n")
+	fmt.Fprintf(w, ".PHONY: configure\n\n")
+
+	fmt.Fprintf(w, "build: configure\n")
+	fmt.Fprintf(w, "\t$(CMAKE) --build $(if $(PRESET),--preset $(PRESET),$(BUILD_DIR) --config $(BUILD_TYPE)) --parallel\n")
+	fmt.Fprintf(w, ".PHONY: build\n\n")
+
+	fmt.Fprintf(w, "test: build\n")
+	fmt.Fprintf(w, "\t$(CTEST) $(if $(PRESET),--preset $(PRESET),--test-dir $(BUILD_DIR) -C $(BUILD_TYPE)) --output-on-failure\n")
+	fmt.Fprintf(w, ".PHONY: test\n\n")
+
+	// A preset carries its own installDir
+	fmt.Fprintf(w, "install: build\n")
+	fmt.Fprintf(w, "\t$(if $(PRESET),$(CMAKE) --build --preset $(PRESET) --target install,$(CMAKE) --install $(BUILD_DIR) --config $(BUILD_TYPE) --prefix $(PREFIX))\n")
+	fmt.Fprintf(w, ".PHONY: install\n\n")
+
+	fmt.Fprintf(w, "clean:\n")
+	fmt.Fprintf(w, "\trm -rf $(if $(PRESET),$(PRESET_BUILD_DIR),$(BUILD_DIR))\n")
+	fmt.Fprintf(w, ".PHONY: clean\n\n")
+}
+
+func (b *Builder) writeMesonBuildTargets(w *strings.Builder) {
+	fmt.Fprintf(w, "configure:\n")
+	fmt.Fprintf(w, "\t$(MESON) setup $(BUILD_DIR) --buildtype=$(BUILD_TYPE) --prefix=$(PREFIX) $(if $(wildcard $(BUILD_DIR)/build.ninja),--reconfigure)\n")
+	fmt.Fprintf(w, ".PHONY: configure\n\n")
+
+	fmt.Fprintf(w, "build: configure\n")
+	fmt.Fprintf(w, "\t$(MESON) compile -C $(BUILD_DIR)\n")
+	fmt.Fprintf(w, ".PHONY: build\n\n")
+
+	fmt.Fprintf(w, "test: build\n")
+	fmt.Fprintf(w, "\t$(MESON) test -C $(BUILD_DIR) --print-errorlogs\n")
+	fmt.Fprintf(w, ".PHONY: test\n\n")
+
+	fmt.Fprintf(w, "install: build\n")
+	fmt.Fprintf(w, "\t$(MESON) install -C $(BUILD_DIR)\n")
+	fmt.Fprintf(w, ".PHONY: install\n\n")
+
+	fmt.Fprintf(w, "clean:\n")
+	fmt.Fprintf(w, "\trm -rf $(BUILD_DIR)\n")
+	fmt.Fprintf(w, ".PHONY: clean\n\n")
+}
+
+// writeAutotoolsBuildTargets builds out of tree so that ./configure does
+// not overwrite this Makefile with its own
+func (b *Builder) writeAutotoolsBuildTargets(w *strings.Builder) {
+	fmt.Fprintf(w, "configure:\n")
+	fmt.Fprintf(w, "\ttest -x configure || autoreconf -fi\n")
+	fmt.Fprintf(w, "\tmkdir -p $(BUILD_DIR)\n")
+	fmt.Fprintf(w, "\tcd $(BUILD_DIR) && ../configure --prefix=$(PREFIX) $(CONFIGURE_FLAGS)\n")
+	fmt.Fprintf(w, ".PHONY: configure\n\n")
+
+	fmt.Fprintf(w, "build: configure\n")
+	fmt.Fprintf(w, "\t$(MAKE) -C $(BUILD_DIR)\n")
+	fmt.Fprintf(w, ".PHONY: build\n\n")
+
+	fmt.Fprintf(w, "test: build\n")
+	fmt.Fprintf(w, "\t$(MAKE) -C $(BUILD_DIR) check\n")
+	fmt.Fprintf(w, ".PHONY: test\n\n")
+
+	fmt.Fprintf(w, "install: build\n")
+	fmt.Fprintf(w, "\t$(MAKE) -C $(BUILD_DIR) install\n")
+	fmt.Fprintf(w, ".PHONY: install\n\n")
+
+	fmt.Fprintf(w, "clean:\n")
+	fmt.Fprintf(w, "\trm -rf $(BUILD_DIR)\n")
+	fmt.Fprintf(w, ".PHONY: clean\n\n")
+}
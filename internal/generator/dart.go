@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/config"
+)
+
+func isFlutter(cfg *config.MakefileConfig) bool {
+	return cfg.Framework != nil && cfg.Framework.Name == "Flutter"
+}
+
+func (b *Builder) writeDartVariables(w *strings.Builder, cfg *config.MakefileConfig) {
+	fmt.Fprintf(w, "DART := dart\n")
+	if isFlutter(cfg) {
+		fmt.Fprintf(w, "FLUTTER := flutter\n")
+		fmt.Fprintf(w, "PLATFORM ?= apk\n")
+	}
+}
+
+func (b *Builder) writeDartBuildTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	// Flutter wraps the dart CLI for everything but formatting
+	tool := "$(DART)"
+	if isFlutter(cfg) {
+		tool = "$(FLUTTER)"
+	}
+
+	fmt.Fprintf(w, "deps:\n")
+	fmt.Fprintf(w, "\t%s pub get\n", tool)
+	fmt.Fprintf(w, ".PHONY: deps\n\n")
+
+	fmt.Fprintf(w, "build: deps\n")
+	if isFlutter(cfg) {
+		fmt.Fprintf(w, "\t$(FLUTTER) build $(PLATFORM)\n")
+	} else {
+		entrypoint := cfg.Entrypoint
+		if entrypoint == "" {
+			entrypoint = "bin/main.dart"
+		}
+		fmt.Fprintf(w, "\tmkdir -p build\n")
+		fmt.Fprintf(w, "\t$(DART) compile exe %s -o build/$(PROJECT_NAME)\n", entrypoint)
+	}
+	fmt.Fprintf(w, ".PHONY: build\n\n")
+
+	fmt.Fprintf(w, "run:\n")
+	fmt.Fprintf(w, "\t%s run\n", tool)
+	fmt.Fprintf(w, ".PHONY: run\n\n")
+
+	fmt.Fprintf(w, "test:\n")
+	fmt.Fprintf(w, "\t%s test\n", tool)
+	fmt.Fprintf(w, ".PHONY: test\n\n")
+
+	fmt.Fprintf(w, "lint:\n")
+	fmt.Fprintf(w, "\t%s analyze\n", tool)
+	fmt.Fprintf(w, ".PHONY: lint\n\n")
+
+	fmt.Fprintf(w, "format:\n")
+	fmt.Fprintf(w, "\t$(DART) format .\n")
+	fmt.Fprintf(w, ".PHONY: format\n\n")
+
+	fmt.Fprintf(w, "format-check:\n")
+	fmt.Fprintf(w, "\t$(DART) format --output=none --set-exit-if-changed .\n")
+	fmt.Fprintf(w, ".PHONY: format-check\n\n")
+
+	fmt.Fprintf(w, "clean:\n")
+	if isFlutter(cfg) {
+		fmt.Fprintf(w, "\t$(FLUTTER) clean\n")
+	} else {
+		fmt.Fprintf(w, "\trm -rf build .dart_tool\n")
+	}
+	fmt.Fprintf(w, ".PHONY: clean\n\n")
+}
@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/config"
+)
+
+func (b *Builder) writeDenoBuildTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	entrypoint := cfg.Entrypoint
+	if entrypoint == "" {
+		entrypoint = "main.ts"
+	}
+
+	// Fresh ships its own dev/build/start tasks in deno.json
+	if cfg.Framework != nil && cfg.Framework.Name == "Fresh" {
+		for _, task := range []string{"dev", "build", "start"} {
+			fmt.Fprintf(w, "%s:\n", task)
+			fmt.Fprintf(w, "\t$(DENO) task %s\n", task)
+			fmt.Fprintf(w, ".PHONY: %s\n\n", task)
+		}
+	} else {
+		fmt.Fprintf(w, "run:\n")
+		fmt.Fprintf(w, "\t$(DENO) run --allow-all %s\n", entrypoint)
+		fmt.Fprintf(w, ".PHONY: run\n\n")
+
+		fmt.Fprintf(w, "dev:\n")
+		fmt.Fprintf(w, "\t$(DENO) run --allow-all --watch %s\n", entrypoint)
+		fmt.Fprintf(w, ".PHONY: dev\n\n")
+
+		fmt.Fprintf(w, "build:\n")
+		fmt.Fprintf(w, "\t$(DENO) compile --allow-all -o bin/$(PROJECT_NAME) %s\n", entrypoint)
+		fmt.Fprintf(w, ".PHONY: build\n\n")
+	}
+
+	fmt.Fprintf(w, "check:\n")
+	fmt.Fprintf(w, "\t$(DENO) check %s\n", entrypoint)
+	fmt.Fprintf(w, ".PHONY: check\n\n")
+
+	fmt.Fprintf(w, "test:\n")
+	fmt.Fprintf(w, "\t$(DENO) test --allow-all\n")
+	fmt.Fprintf(w, ".PHONY: test\n\n")
+
+	fmt.Fprintf(w, "lint:\n")
+	fmt.Fprintf(w, "\t$(DENO) lint\n")
+	fmt.Fprintf(w, ".PHONY: lint\n\n")
+
+	fmt.Fprintf(w, "format:\n")
+	fmt.Fprintf(w, "\t$(DENO) fmt\n")
+	fmt.Fprintf(w, ".PHONY: format\n\n")
+
+	fmt.Fprintf(w, "format-check:\n")
+	fmt.Fprintf(w, "\t$(DENO) fmt --check\n")
+	fmt.Fprintf(w, ".PHONY: format-check\n\n")
+}
@@ -0,0 +1,212 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/config"
+	"github.com/gaoubak/Makegen/internal/storage"
+)
+
+// requirement is an executable the generated recipes call
+type requirement struct {
+	bin        string
+	min        string // minimum version, empty when any version works
+	versionArg string // prints the version, "--version" when empty
+	install    string
+	uses       []string // recipe fragments that call the executable
+}
+
+// toolCheck defines a shell function check <bin> <min> <install> <version
+// arg> shared by doctor and the require-* guards
+const toolCheck = `TOOL_CHECK = check() { \
+	if ! command -v "$$1" >/dev/null 2>&1; then \
+		echo "✗ $$1 not found, install: $$3" >&2; return 1; \
+	fi; \
+	if [ -n "$$2" ]; then \
+		v=$$("$$1" $$4 2>&1 | grep -oE '[0-9]+\.[0-9]+(\.[0-9]+)?' | head -n 1); \
+		if [ -n "$$v" ] && [ "$$(printf '%s\n%s\n' "$$2" "$$v" | sort -V | head -n 1)" != "$$2" ]; then \
+			echo "✗ $$1 $$v is older than $$2, upgrade: $$3" >&2; return 1; \
+		fi; \
+	fi; \
+	echo "✓ $$1"; \
+}
+`
+
+// toolRequirements lists the executables makegen knows how to check
+func toolRequirements(cfg *config.MakefileConfig) []requirement {
+	packageManager := cfg.PackageManager
+	if packageManager == "" {
+		packageManager = "npm"
+	}
+	packageManagerHint := "https://nodejs.org"
+	if packageManager != "npm" {
+		packageManagerHint = "corepack enable " + packageManager
+	}
+
+	// Wrapper scripts only need a JDK
+	mvn := requirement{bin: "mvn", install: "https://maven.apache.org/install.html", uses: []string{"$(MVN)"}}
+	gradle := requirement{bin: "gradle", install: "https://gradle.org/install/", uses: []string{"$(GRADLE)"}}
+	java := requirement{bin: "java", versionArg: "-version", install: "https://adoptium.net"}
+	if cfg.UseWrapper {
+		java.uses = []string{"$(MVN)", "$(GRADLE)"}
+		mvn.uses, gradle.uses = nil, nil
+	}
+
+	return []requirement{
+		{bin: "go", min: "1.23", versionArg: "version", install: "https://go.dev/dl/", uses: []string{"$(GO) ", "go install"}},
+		{bin: "node", min: "18", install: "https://nodejs.org", uses: []string{"$(NODE)", "$(NPM)", "npx "}},
+		{bin: packageManager, install: packageManagerHint, uses: []string{"$(NPM)"}},
+		{bin: "python3", min: "3.8", install: "https://www.python.org/downloads/", uses: []string{"$(PYTHON)"}},
+		{bin: "pip3", install: "python3 -m ensurepip", uses: []string{"$(PIP)"}},
+		{bin: "cargo", install: "https://rustup.rs", uses: []string{"cargo "}},
+		{bin: "cargo-llvm-cov", install: "cargo install cargo-llvm-cov", uses: []string{"cargo llvm-cov"}},
+		{bin: "cargo-nextest", install: "cargo install cargo-nextest --locked", uses: []string{"cargo nextest", "llvm-cov nextest"}},
+		mvn,
+		gradle,
+		java,
+		{bin: "bundle", install: "gem install bundler", uses: []string{"$(BUNDLE)"}},
+		{bin: "php", install: "https://www.php.net/downloads", uses: []string{"$(PHP)"}},
+		{bin: "composer", install: "https://getcomposer.org/download/", uses: []string{"$(COMPOSER)"}},
+		{bin: "cmake", install: "https://cmake.org/download/", uses: []string{"$(CMAKE)"}},
+		{bin: "meson", install: "pipx install meson ninja", uses: []string{"$(MESON)"}},
+		{bin: "mix", install: "https://elixir-lang.org/install.html", uses: []string{"$(MIX)"}},
+		{bin: "dotnet", install: "https://dotnet.microsoft.com/download", uses: []string{"$(DOTNET)"}},
+		{bin: "dart", install: "https://dart.dev/get-dart", uses: []string{"$(DART)"}},
+		{bin: "flutter", install: "https://docs.flutter.dev/get-started/install", uses: []string{"$(FLUTTER)"}},
+		{bin: "deno", install: "https://docs.deno.com/runtime/getting_started/installation/", uses: []string{"$(DENO)"}},
+		{bin: "zig", install: "https://ziglang.org/download/", uses: []string{"$(ZIG)"}},
+		{bin: "swift", install: "https://www.swift.org/install/", uses: []string{"$(SWIFT)"}},
+		{bin: "stack", install: "https://docs.haskellstack.org/en/stable/install_and_upgrade/", uses: []string{"$(STACK)"}},
+		{bin: "cabal", install: "https://www.haskell.org/ghcup/", uses: []string{"$(CABAL)"}},
+		{bin: "docker", install: "https://docs.docker.com/get-docker/", uses: []string{"$(DOCKER)"}},
+		{bin: "docker-compose", install: "https://docs.docker.com/compose/install/", uses: []string{"docker-compose"}},
+		{bin: "golangci-lint", install: "https://golangci-lint.run/welcome/install/", uses: []string{"golangci-lint "}},
+		{bin: "buf", install: "https://buf.build/docs/installation", uses: []string{"buf generate"}},
+		{bin: "protoc", install: "https://grpc.io/docs/protoc-installation/", uses: []string{"protoc "}},
+		{bin: "sqlc", install: "go install github.com/sqlc-dev/sqlc/cmd/sqlc@latest", uses: []string{"sqlc "}},
+		{bin: "oapi-codegen", install: "go install github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@latest", uses: []string{"oapi-codegen "}},
+		{bin: "mockery", install: "go install github.com/vektra/mockery/v2@latest", uses: []string{"mockery"}},
+		{bin: "migrate", install: "https://github.com/golang-migrate/migrate/tree/master/cmd/migrate", uses: []string{"migrate -"}},
+		{bin: "goose", install: "go install github.com/pressly/goose/v3/cmd/goose@latest", uses: []string{"goose "}},
+		{bin: "alembic", install: "pip install alembic", uses: []string{"alembic "}},
+		{bin: "flyway", install: "https://documentation.red-gate.com/fd/command-line-184127404.html", uses: []string{"flyway "}},
+		{bin: "liquibase", install: "https://www.liquibase.com/download", uses: []string{"liquibase "}},
+		{bin: "psql", install: "https://www.postgresql.org/download/", uses: []string{"psql "}},
+	}
+}
+
+// usesTool reports whether a recipe calls the executable. Fragments must
+// start a word so that e.g. grpc_tools.protoc is not taken for protoc.
+func usesTool(recipe string, req requirement) bool {
+	recipe = " " + strings.NewReplacer("\t", " ", "@", " ").Replace(recipe)
+	for _, use := range req.uses {
+		if strings.Contains(recipe, " "+use) {
+			return true
+		}
+	}
+	return false
+}
+
+// referencedRequirements maps every generated target to the executables
+// its recipe calls. Pinned tools are installed by `make tools` instead.
+func referencedRequirements(cfg *config.MakefileConfig, makefile string) ([]requirement, map[string][]string) {
+	parsed := storage.ParseMakefile(makefile)
+	guards := make(map[string][]string)
+	var required []requirement
+
+	for _, req := range toolRequirements(cfg) {
+		if len(req.uses) == 0 || isPinned(cfg, req.bin) {
+			continue
+		}
+
+		used := false
+		for _, block := range parsed.Blocks {
+			if block.Kind != storage.BlockRule || len(block.Names) != 1 {
+				continue
+			}
+			if !usesTool(recipeLines(block.Text), req) {
+				continue
+			}
+			used = true
+
+			// Guarding a file target would rebuild it every time
+			if target := block.Names[0]; parsed.Phony[target] {
+				guards[target] = append(guards[target], req.bin)
+			}
+		}
+		if used {
+			required = append(required, req)
+		}
+	}
+
+	return required, guards
+}
+
+// recipeLines returns the tab-indented recipe of a rule block, leaving out
+// the comments and the rule line itself
+func recipeLines(text string) string {
+	var recipe []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, "\t") {
+			recipe = append(recipe, line)
+		}
+	}
+	return strings.Join(recipe, "\n")
+}
+
+// checkCall is the TOOL_CHECK invocation for one requirement
+func checkCall(req requirement) string {
+	versionArg := req.versionArg
+	if versionArg == "" {
+		versionArg = "--version"
+	}
+	return fmt.Sprintf("check %s \"%s\" \"%s\" %s", req.bin, req.min, req.install, versionArg)
+}
+
+// writeDoctorTargets appends doctor, which reports every missing or
+// outdated executable, and with guards enabled a require-<bin> target per
+// executable added as a prerequisite of the targets calling it
+func (b *Builder) writeDoctorTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	required, guards := referencedRequirements(cfg, w.String())
+	if len(required) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "# Doctor Targets\n")
+	fmt.Fprintf(w, "%s\n", toolCheck)
+
+	fmt.Fprintf(w, "doctor:\n")
+	fmt.Fprintf(w, "\t@$(TOOL_CHECK); status=0; \\\n")
+	for _, req := range required {
+		fmt.Fprintf(w, "\t%s || status=1; \\\n", checkCall(req))
+	}
+	fmt.Fprintf(w, "\texit $$status\n")
+	fmt.Fprintf(w, ".PHONY: doctor\n\n")
+
+	if !cfg.EnableGuards {
+		return
+	}
+
+	for _, req := range required {
+		fmt.Fprintf(w, "require-%s:\n", req.bin)
+		fmt.Fprintf(w, "\t@$(TOOL_CHECK); %s >/dev/null\n", checkCall(req))
+		fmt.Fprintf(w, ".PHONY: require-%s\n\n", req.bin)
+	}
+
+	targets := make([]string, 0, len(guards))
+	for target := range guards {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	for _, target := range targets {
+		var prereqs []string
+		for _, bin := range guards[target] {
+			prereqs = append(prereqs, "require-"+bin)
+		}
+		fmt.Fprintf(w, "%s: %s\n", target, strings.Join(prereqs, " "))
+	}
+	fmt.Fprintf(w, "\n")
+}
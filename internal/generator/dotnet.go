@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/config"
+)
+
+func (b *Builder) writeDotnetBuildTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	fmt.Fprintf(w, "restore:\n")
+	fmt.Fprintf(w, "\t$(DOTNET) restore $(SOLUTION)\n")
+	fmt.Fprintf(w, ".PHONY: restore\n\n")
+
+	fmt.Fprintf(w, "build: restore\n")
+	fmt.Fprintf(w, "\t$(DOTNET) build $(SOLUTION) -c $(CONFIGURATION) --no-restore\n")
+	fmt.Fprintf(w, ".PHONY: build\n\n")
+
+	// A solution holds several projects: pick one with PROJECT=<path>
+	fmt.Fprintf(w, "run:\n")
+	if strings.HasSuffix(cfg.Entrypoint, ".sln") {
+		fmt.Fprintf(w, "\t$(DOTNET) run -c $(CONFIGURATION) $(if $(PROJECT),--project $(PROJECT))\n")
+	} else {
+		fmt.Fprintf(w, "\t$(DOTNET) run -c $(CONFIGURATION) --project $(SOLUTION)\n")
+	}
+	fmt.Fprintf(w, ".PHONY: run\n\n")
+
+	fmt.Fprintf(w, "test:\n")
+	fmt.Fprintf(w, "\t$(DOTNET) test $(SOLUTION) -c $(CONFIGURATION)\n")
+	fmt.Fprintf(w, ".PHONY: test\n\n")
+
+	fmt.Fprintf(w, "publish:\n")
+	fmt.Fprintf(w, "\t$(DOTNET) publish $(SOLUTION) -c Release -o out\n")
+	fmt.Fprintf(w, ".PHONY: publish\n\n")
+
+	fmt.Fprintf(w, "format:\n")
+	fmt.Fprintf(w, "\t$(DOTNET) format $(SOLUTION)\n")
+	fmt.Fprintf(w, ".PHONY: format\n\n")
+
+	fmt.Fprintf(w, "format-check:\n")
+	fmt.Fprintf(w, "\t$(DOTNET) format $(SOLUTION) --verify-no-changes\n")
+	fmt.Fprintf(w, ".PHONY: format-check\n\n")
+
+	fmt.Fprintf(w, "clean:\n")
+	fmt.Fprintf(w, "\t$(DOTNET) clean $(SOLUTION)\n")
+	fmt.Fprintf(w, "\trm -rf out\n")
+	fmt.Fprintf(w, ".PHONY: clean\n\n")
+}
@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/config"
+)
+
+func (b *Builder) writeElixirBuildTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	phoenix := cfg.Framework != nil && cfg.Framework.Name == "Phoenix"
+
+	fmt.Fprintf(w, "deps:\n")
+	fmt.Fprintf(w, "\t$(MIX) deps.get\n")
+	fmt.Fprintf(w, ".PHONY: deps\n\n")
+
+	fmt.Fprintf(w, "build: deps\n")
+	fmt.Fprintf(w, "\t$(MIX) compile\n")
+	fmt.Fprintf(w, ".PHONY: build\n\n")
+
+	fmt.Fprintf(w, "run:\n")
+	if phoenix {
+		fmt.Fprintf(w, "\t$(MIX) phx.server\n")
+	} else {
+		fmt.Fprintf(w, "\t$(MIX) run --no-halt\n")
+	}
+	fmt.Fprintf(w, ".PHONY: run\n\n")
+
+	fmt.Fprintf(w, "console:\n")
+	fmt.Fprintf(w, "\tiex -S mix\n")
+	fmt.Fprintf(w, ".PHONY: console\n\n")
+
+	fmt.Fprintf(w, "test:\n")
+	fmt.Fprintf(w, "\t$(MIX) test\n")
+	fmt.Fprintf(w, ".PHONY: test\n\n")
+
+	fmt.Fprintf(w, "format:\n")
+	fmt.Fprintf(w, "\t$(MIX) format\n")
+	fmt.Fprintf(w, ".PHONY: format\n\n")
+
+	fmt.Fprintf(w, "format-check:\n")
+	fmt.Fprintf(w, "\t$(MIX) format --check-formatted\n")
+	fmt.Fprintf(w, ".PHONY: format-check\n\n")
+
+	fmt.Fprintf(w, "deps-update:\n")
+	fmt.Fprintf(w, "\t$(MIX) deps.update --all\n")
+	fmt.Fprintf(w, ".PHONY: deps-update\n\n")
+
+	fmt.Fprintf(w, "clean:\n")
+	fmt.Fprintf(w, "\t$(MIX) clean\n")
+	fmt.Fprintf(w, ".PHONY: clean\n\n")
+}
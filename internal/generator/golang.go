@@ -0,0 +1,191 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/config"
+)
+
+// goBinary is a main package and the name of the binary built from it
+type goBinary struct {
+	name string
+	pkg  string
+}
+
+// goBinaries lists the binaries to build, one per cmd/<name> main package.
+// The root package builds as $(PROJECT_NAME).
+func goBinaries(cfg *config.MakefileConfig) []goBinary {
+	var binaries []goBinary
+	for _, pkg := range cfg.Binaries {
+		if pkg == "." {
+			binaries = append(binaries, goBinary{name: cfg.ProjectName, pkg: "."})
+		} else {
+			binaries = append(binaries, goBinary{name: path.Base(pkg), pkg: "./" + pkg})
+		}
+	}
+	return binaries
+}
+
+// goForEachModule returns the recipe running cmd in every module of a
+// go.work workspace, since ./... at the workspace root does not reach them.
+// Single-module projects run cmd once at the root.
+func goForEachModule(cfg *config.MakefileConfig, cmd string) string {
+	if len(cfg.Modules) == 0 {
+		return "\t" + cmd + "\n"
+	}
+
+	return "\t@for mod in $(GO_MODULES); do \\\n" +
+		"\t\techo \"==> $$mod\"; \\\n" +
+		"\t\t(cd $$mod && " + cmd + ") || exit 1; \\\n" +
+		"\tdone\n"
+}
+
+// goRecipeLine is goForEachModule without the leading tab and trailing
+// newline, for recipes collected as a list of lines
+func goRecipeLine(cfg *config.MakefileConfig, cmd string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(goForEachModule(cfg, cmd), "\t"), "\n")
+}
+
+func (b *Builder) writeGoBuildTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	b.writeGoBinaryTargets(w, cfg)
+	b.writeGoModuleTargets(w, cfg)
+
+	if cfg.EnableRelease {
+		b.writeGoReleaseTargets(w, cfg)
+	}
+}
+
+func (b *Builder) writeGoBinaryTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	binaries := goBinaries(cfg)
+
+	// A workspace root without main packages just compiles every module
+	if len(binaries) == 0 && len(cfg.Modules) > 0 {
+		fmt.Fprintf(w, "build:\n")
+		fmt.Fprintf(w, "%s", goForEachModule(cfg, "$(GO) build $(GOFLAGS) ./..."))
+		fmt.Fprintf(w, ".PHONY: build\n\n")
+
+		b.writeGoCleanTarget(w)
+		return
+	}
+
+	// A single root package keeps the simple build/run pair
+	if len(binaries) == 0 || (len(binaries) == 1 && binaries[0].pkg == ".") {
+		fmt.Fprintf(w, "build:\n")
+		fmt.Fprintf(w, "\t$(GO) build $(GOFLAGS) -o $(OUT_DIR)/$(PROJECT_NAME) .\n")
+		fmt.Fprintf(w, ".PHONY: build\n\n")
+
+		b.writeGoCleanTarget(w)
+
+		fmt.Fprintf(w, "run: build\n")
+		fmt.Fprintf(w, "\t./$(OUT_DIR)/$(PROJECT_NAME)\n")
+		fmt.Fprintf(w, ".PHONY: run\n\n")
+		return
+	}
+
+	var buildTargets []string
+	for _, bin := range binaries {
+		buildTargets = append(buildTargets, "build-"+bin.name)
+	}
+
+	fmt.Fprintf(w, "build: %s\n", strings.Join(buildTargets, " "))
+	fmt.Fprintf(w, ".PHONY: build\n\n")
+
+	for _, bin := range binaries {
+		fmt.Fprintf(w, "build-%s:\n", bin.name)
+		fmt.Fprintf(w, "\t$(GO) build $(GOFLAGS) -o $(OUT_DIR)/%s %s\n", bin.name, bin.pkg)
+		fmt.Fprintf(w, ".PHONY: build-%s\n\n", bin.name)
+
+		fmt.Fprintf(w, "run-%s: build-%s\n", bin.name, bin.name)
+		fmt.Fprintf(w, "\t./$(OUT_DIR)/%s $(ARGS)\n", bin.name)
+		fmt.Fprintf(w, ".PHONY: run-%s\n\n", bin.name)
+	}
+
+	// run starts the binary named after the project, or the first one
+	primary := binaries[0]
+	for _, bin := range binaries {
+		if bin.name == cfg.ProjectName {
+			primary = bin
+		}
+	}
+	fmt.Fprintf(w, "run: run-%s\n", primary.name)
+	fmt.Fprintf(w, ".PHONY: run\n\n")
+
+	if cfg.ModulePath != "" {
+		packages := "$(MODULE)/cmd/..."
+		if binaries[0].pkg == "." {
+			packages = "$(MODULE) " + packages
+		}
+		fmt.Fprintf(w, "install:\n")
+		fmt.Fprintf(w, "\t$(GO) install %s\n", packages)
+		fmt.Fprintf(w, ".PHONY: install\n\n")
+	}
+
+	b.writeGoCleanTarget(w)
+}
+
+func (b *Builder) writeGoModuleTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	fmt.Fprintf(w, "vet:\n")
+	fmt.Fprintf(w, "%s", goForEachModule(cfg, "$(GO) vet ./..."))
+	fmt.Fprintf(w, ".PHONY: vet\n\n")
+
+	fmt.Fprintf(w, "tidy:\n")
+	fmt.Fprintf(w, "%s", goForEachModule(cfg, "$(GO) mod tidy"))
+	fmt.Fprintf(w, ".PHONY: tidy\n\n")
+
+	// Fails when go mod tidy would change go.mod or go.sum
+	fmt.Fprintf(w, "tidy-check:\n")
+	fmt.Fprintf(w, "%s", goForEachModule(cfg, "$(GO) mod tidy -diff"))
+	fmt.Fprintf(w, ".PHONY: tidy-check\n\n")
+
+	if len(cfg.Modules) > 0 {
+		fmt.Fprintf(w, "work-sync:\n")
+		fmt.Fprintf(w, "\t$(GO) work sync\n")
+		fmt.Fprintf(w, ".PHONY: work-sync\n\n")
+	}
+}
+
+func (b *Builder) writeGoCleanTarget(w *strings.Builder) {
+	fmt.Fprintf(w, "clean:\n")
+	fmt.Fprintf(w, "\trm -rf $(OUT_DIR)\n")
+	fmt.Fprintf(w, "\t$(GO) clean\n")
+	fmt.Fprintf(w, ".PHONY: clean\n\n")
+}
+
+func (b *Builder) writeGoReleaseVariables(w *strings.Builder, cfg *config.MakefileConfig) {
+	fmt.Fprintf(w, "PLATFORMS ?= %s\n", strings.Join(cfg.Platforms, " "))
+	fmt.Fprintf(w, "DIST_DIR := dist\n")
+	fmt.Fprintf(w, "RELEASE_LDFLAGS ?= -s -w -X main.version=$(VERSION)\n")
+	fmt.Fprintf(w, "SHA256SUM ?= sha256sum\n")
+}
+
+// writeGoReleaseTargets cross-compiles every binary for each of $(PLATFORMS)
+// into dist/<name>-<version>-<os>-<arch>, archives it (zip on Windows,
+// tar.gz elsewhere) and writes a checksums file
+func (b *Builder) writeGoReleaseTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	binaries := goBinaries(cfg)
+	if len(binaries) == 0 {
+		binaries = []goBinary{{name: "$(PROJECT_NAME)", pkg: "."}}
+	}
+
+	fmt.Fprintf(w, "# Release Targets\n")
+	fmt.Fprintf(w, "release:\n")
+	fmt.Fprintf(w, "\t@rm -rf $(DIST_DIR) && mkdir -p $(DIST_DIR)\n")
+	fmt.Fprintf(w, "\t@for platform in $(PLATFORMS); do \\\n")
+	fmt.Fprintf(w, "\t\tos=$${platform%%/*}; arch=$${platform#*/}; \\\n")
+	fmt.Fprintf(w, "\t\text=; if [ \"$$os\" = windows ]; then ext=.exe; fi; \\\n")
+	for _, bin := range binaries {
+		fmt.Fprintf(w, "\t\tname=%s-$(VERSION)-$$os-$$arch; \\\n", bin.name)
+		fmt.Fprintf(w, "\t\techo \"Building $$name\"; \\\n")
+		fmt.Fprintf(w, "\t\tCGO_ENABLED=0 GOOS=$$os GOARCH=$$arch $(GO) build -trimpath -ldflags \"$(RELEASE_LDFLAGS)\" -o $(DIST_DIR)/$$name$$ext %s || exit 1; \\\n", bin.pkg)
+		fmt.Fprintf(w, "\t\tif [ \"$$os\" = windows ]; then \\\n")
+		fmt.Fprintf(w, "\t\t\t(cd $(DIST_DIR) && zip -q $$name.zip $$name$$ext) || exit 1; \\\n")
+		fmt.Fprintf(w, "\t\telse \\\n")
+		fmt.Fprintf(w, "\t\t\ttar -czf $(DIST_DIR)/$$name.tar.gz -C $(DIST_DIR) $$name || exit 1; \\\n")
+		fmt.Fprintf(w, "\t\tfi; \\\n")
+	}
+	fmt.Fprintf(w, "\tdone\n")
+	fmt.Fprintf(w, "\tcd $(DIST_DIR) && $(SHA256SUM) $$(ls *.tar.gz *.zip 2>/dev/null) > checksums.txt\n")
+	fmt.Fprintf(w, ".PHONY: release\n\n")
+}
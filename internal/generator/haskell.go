@@ -0,0 +1,36 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/config"
+)
+
+// haskellTarget maps a Makefile target to its Stack and Cabal commands
+type haskellTarget struct {
+	name  string
+	stack string
+	cabal string
+}
+
+var haskellTargets = []haskellTarget{
+	{name: "deps", stack: "build --only-dependencies", cabal: "build --only-dependencies all"},
+	{name: "build", stack: "build", cabal: "build all"},
+	{name: "run", stack: "run", cabal: "run"},
+	{name: "test", stack: "test", cabal: "test all"},
+	{name: "repl", stack: "ghci", cabal: "repl"},
+	{name: "clean", stack: "clean", cabal: "clean"},
+}
+
+func (b *Builder) writeHaskellBuildTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	for _, target := range haskellTargets {
+		fmt.Fprintf(w, "%s:\n", target.name)
+		if cfg.BuildTool == "stack" {
+			fmt.Fprintf(w, "\t$(STACK) %s\n", target.stack)
+		} else {
+			fmt.Fprintf(w, "\t$(CABAL) %s\n", target.cabal)
+		}
+		fmt.Fprintf(w, ".PHONY: %s\n\n", target.name)
+	}
+}
@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/config"
+)
+
+// jvmTarget maps a Makefile target to its Maven goal and Gradle task
+type jvmTarget struct {
+	name   string
+	maven  string
+	gradle string
+	note   string // comment written above the Gradle rule
+}
+
+// jvmTargets lists the build targets shared by Maven and Gradle projects.
+// Gradle has no built-in dependency update tasks, so those need plugins.
+var jvmTargets = []jvmTarget{
+	{name: "compile", maven: "compile", gradle: "classes"},
+	{name: "test", maven: "test", gradle: "test"},
+	{name: "package", maven: "package -DskipTests", gradle: "assemble"},
+	{name: "run", maven: "exec:java $(if $(MAIN_CLASS),-Dexec.mainClass=$(MAIN_CLASS))", gradle: "run"},
+	{name: "clean", maven: "clean", gradle: "clean"},
+	{name: "deps-outdated", maven: "versions:display-dependency-updates", gradle: "dependencyUpdates",
+		note: "Needs the com.github.ben-manes.versions plugin"},
+	{name: "deps-update", maven: "versions:update-properties versions:use-latest-releases -DgenerateBackupPoms=false", gradle: "useLatestVersions",
+		note: "Needs the se.patrikerdes.use-latest-versions and com.github.ben-manes.versions plugins"},
+}
+
+func (b *Builder) writeJVMVariables(w *strings.Builder, cfg *config.MakefileConfig) {
+	switch cfg.BuildTool {
+	case "gradle":
+		if cfg.UseWrapper {
+			fmt.Fprintf(w, "GRADLE := ./gradlew\n")
+		} else {
+			fmt.Fprintf(w, "GRADLE := gradle\n")
+		}
+		fmt.Fprintf(w, "GRADLE_FLAGS := --console=plain\n")
+	default:
+		if cfg.UseWrapper {
+			fmt.Fprintf(w, "MVN := ./mvnw\n")
+		} else {
+			fmt.Fprintf(w, "MVN := mvn\n")
+		}
+		fmt.Fprintf(w, "MVN_FLAGS := -B\n")
+		// exec:java needs a main class unless the pom configures one
+		if cfg.Framework == nil || cfg.Framework.Name != "Spring Boot" {
+			fmt.Fprintf(w, "MAIN_CLASS ?=\n")
+		}
+	}
+
+	// Multi-module builds can scope every target with MODULE=<name>
+	if len(cfg.Modules) > 0 {
+		fmt.Fprintf(w, "MODULES := %s\n", strings.Join(cfg.Modules, " "))
+		fmt.Fprintf(w, "MODULE ?=\n")
+	}
+}
+
+func (b *Builder) writeJVMBuildTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	springBoot := cfg.Framework != nil && cfg.Framework.Name == "Spring Boot"
+	multiModule := len(cfg.Modules) > 0
+
+	for _, target := range jvmTargets {
+		switch cfg.BuildTool {
+		case "gradle":
+			task := target.gradle
+			if target.name == "run" && springBoot {
+				task = "bootRun"
+			}
+			if multiModule && !strings.HasPrefix(target.name, "deps-") {
+				task = "$(if $(MODULE),:$(MODULE):)" + task
+			}
+			if target.note != "" {
+				fmt.Fprintf(w, "# %s\n", target.note)
+			}
+			fmt.Fprintf(w, "%s:\n", target.name)
+			fmt.Fprintf(w, "\t$(GRADLE) $(GRADLE_FLAGS) %s\n", task)
+		default:
+			goal := target.maven
+			if target.name == "run" && springBoot {
+				goal = "spring-boot:run"
+			}
+			fmt.Fprintf(w, "%s:\n", target.name)
+			switch {
+			case target.name == "run" && multiModule:
+				// -am would run the goal in every upstream module too, so
+				// they are only compiled along with MODULE
+				fmt.Fprintf(w, "\t$(MVN) $(MVN_FLAGS) $(if $(MODULE),-pl $(MODULE) -am) compile\n")
+				fmt.Fprintf(w, "\t$(MVN) $(MVN_FLAGS) $(if $(MODULE),-pl $(MODULE)) %s\n", goal)
+			case target.name == "run" && !springBoot:
+				// exec:java runs the classes as they are, without compiling
+				fmt.Fprintf(w, "\t$(MVN) $(MVN_FLAGS) compile %s\n", goal)
+			case multiModule:
+				fmt.Fprintf(w, "\t$(MVN) $(MVN_FLAGS) $(if $(MODULE),-pl $(MODULE) -am) %s\n", goal)
+			default:
+				fmt.Fprintf(w, "\t$(MVN) $(MVN_FLAGS) %s\n", goal)
+			}
+		}
+
+		fmt.Fprintf(w, ".PHONY: %s\n\n", target.name)
+	}
+}
@@ -0,0 +1,119 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/config"
+)
+
+// lintTool holds the check and fix commands of a linter or formatter.
+// check never modifies files; fix is empty when the tool cannot fix.
+type lintTool struct {
+	check     string
+	fix       string
+	perModule bool // run inside every module of a Go workspace
+}
+
+// lintTools maps the tool names selected by the detector to their commands
+var lintTools = map[string]lintTool{
+	"golangci-lint": {check: "golangci-lint run ./...", fix: "golangci-lint run --fix ./...", perModule: true},
+	"gofmt":         {check: `@out=$$(gofmt -s -l .); if [ -n "$$out" ]; then echo "$$out"; exit 1; fi`, fix: "gofmt -s -w ."},
+	"eslint":        {check: "npx eslint .", fix: "npx eslint --fix ."},
+	"prettier":      {check: "npx prettier --check .", fix: "npx prettier --write ."},
+	"ruff":          {check: "$(PYTHON) -m ruff check .", fix: "$(PYTHON) -m ruff check --fix ."},
+	"ruff-format":   {check: "$(PYTHON) -m ruff format --check .", fix: "$(PYTHON) -m ruff format ."},
+	"black":         {check: "$(PYTHON) -m black --check .", fix: "$(PYTHON) -m black ."},
+	"pylint":        {check: "$(PYTHON) -m pylint --recursive=y ."},
+	"mypy":          {check: "$(PYTHON) -m mypy ."},
+	"clippy":        {check: "cargo clippy --all-targets -- -D warnings", fix: "cargo clippy --fix --allow-dirty --allow-staged"},
+	"rustfmt":       {check: "cargo fmt --check", fix: "cargo fmt"},
+	"rubocop":       {check: "$(BUNDLE) exec rubocop", fix: "$(BUNDLE) exec rubocop -a"},
+	"php-cs-fixer":  {check: "vendor/bin/php-cs-fixer fix --dry-run --diff", fix: "vendor/bin/php-cs-fixer fix"},
+}
+
+// lookupLintTool returns the commands of a known tool. Anything else is
+// taken as a raw check command.
+func lookupLintTool(cfg *config.MakefileConfig, name string) lintTool {
+	if tool, ok := lintTools[name]; ok {
+		return tool
+	}
+	return lintTool{check: name, perModule: cfg.Language == "go"}
+}
+
+// writeToolCommand writes one recipe line, looping over Go workspace
+// modules when the tool runs per module
+func writeToolCommand(w *strings.Builder, cfg *config.MakefileConfig, tool lintTool, cmd string) {
+	cmd = pinCommand(cfg, cmd)
+	if tool.perModule && cfg.Language == "go" {
+		fmt.Fprintf(w, "%s", goForEachModule(cfg, cmd))
+	} else {
+		fmt.Fprintf(w, "\t%s\n", cmd)
+	}
+}
+
+// writeToolTargets writes a checking target and, when at least one tool can
+// fix, the matching fixing target
+func writeToolTargets(w *strings.Builder, cfg *config.MakefileConfig, names []string, checkTarget, fixTarget string) {
+	fmt.Fprintf(w, "%s:%s\n", checkTarget, toolsPrereq(cfg, names))
+	for _, name := range names {
+		tool := lookupLintTool(cfg, name)
+		writeToolCommand(w, cfg, tool, tool.check)
+	}
+	fmt.Fprintf(w, ".PHONY: %s\n\n", checkTarget)
+
+	var fixers []lintTool
+	for _, name := range names {
+		if tool := lookupLintTool(cfg, name); tool.fix != "" {
+			fixers = append(fixers, tool)
+		}
+	}
+	if len(fixers) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "%s:%s\n", fixTarget, toolsPrereq(cfg, names))
+	for _, tool := range fixers {
+		writeToolCommand(w, cfg, tool, tool.fix)
+	}
+	fmt.Fprintf(w, ".PHONY: %s\n\n", fixTarget)
+}
+
+func (b *Builder) writeLintTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	if len(cfg.LintTools) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "# Lint Targets\n")
+	writeToolTargets(w, cfg, cfg.LintTools, "lint", "lint-fix")
+}
+
+// writeFormatTargets writes format, which rewrites files, and format-check,
+// which fails on unformatted files
+func (b *Builder) writeFormatTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	if len(cfg.FormatTools) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "# Format Targets\n")
+
+	fmt.Fprintf(w, "format:%s\n", toolsPrereq(cfg, cfg.FormatTools))
+	for _, name := range cfg.FormatTools {
+		tool := lookupLintTool(cfg, name)
+		cmd := tool.fix
+		if cmd == "" {
+			cmd = tool.check
+		}
+		writeToolCommand(w, cfg, tool, cmd)
+	}
+	fmt.Fprintf(w, ".PHONY: format\n\n")
+
+	// A raw format command has no check mode
+	fmt.Fprintf(w, "format-check:%s\n", toolsPrereq(cfg, cfg.FormatTools))
+	for _, name := range cfg.FormatTools {
+		if tool, ok := lintTools[name]; ok {
+			writeToolCommand(w, cfg, tool, tool.check)
+		}
+	}
+	fmt.Fprintf(w, ".PHONY: format-check\n\n")
+}
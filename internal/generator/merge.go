@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/storage"
+)
+
+// preservedHeader introduces the section holding content carried over from
+// an existing Makefile
+const preservedHeader = "# Preserved Targets"
+
+// Owned names the targets and variables an earlier run generated
+type Owned struct {
+	Targets   []string `json:"targets"`
+	Variables []string `json:"variables"`
+}
+
+// Merge appends the rules, variables and directives of an existing Makefile
+// that the generated content does not define, so regenerating never drops
+// hand-written targets. Those an earlier run generated, named in owned, are
+// dropped instead once they are no longer generated. It returns the merged
+// content and the names of the preserved targets.
+func Merge(generated string, existing *storage.ParsedMakefile, owned Owned) (string, []string) {
+	gen := storage.ParseMakefile(generated)
+
+	genTargets := make(map[string]bool)
+	for _, target := range gen.Targets() {
+		genTargets[target] = true
+	}
+	genVars := make(map[string]bool)
+	for _, name := range gen.Variables() {
+		genVars[name] = true
+	}
+	ownedTargets := make(map[string]bool)
+	for _, target := range owned.Targets {
+		ownedTargets[target] = true
+	}
+	ownedVars := make(map[string]bool)
+	for _, name := range owned.Variables {
+		ownedVars[name] = true
+	}
+
+	var blocks []string
+	var preserved []string
+
+	for _, block := range existing.Blocks {
+		text := strings.TrimPrefix(block.Text, preservedHeader+"\n")
+
+		switch block.Kind {
+		case storage.BlockRule:
+			if len(block.Names) == 1 && block.Names[0] == ".PHONY" {
+				continue
+			}
+
+			var phony []string
+			keep := false
+			for _, name := range block.Names {
+				if !genTargets[name] && !ownedTargets[name] {
+					keep = true
+				}
+				if existing.Phony[name] {
+					phony = append(phony, name)
+				}
+			}
+			if !keep || (strings.HasPrefix(block.Names[0], ".") && strings.Contains(generated, text)) {
+				continue
+			}
+
+			if len(phony) > 0 {
+				text += "\n.PHONY: " + strings.Join(phony, " ")
+			}
+			blocks = append(blocks, text)
+			preserved = append(preserved, block.Names...)
+
+		case storage.BlockVariable:
+			if !genVars[block.Names[0]] && !ownedVars[block.Names[0]] {
+				blocks = append(blocks, text)
+			}
+
+		case storage.BlockDirective:
+			if len(block.Names) > 0 && (genVars[block.Names[0]] || ownedVars[block.Names[0]]) {
+				continue
+			}
+			if !strings.Contains(generated, text) {
+				blocks = append(blocks, text)
+			}
+		}
+	}
+
+	if len(blocks) == 0 {
+		return generated, nil
+	}
+
+	var merged strings.Builder
+	merged.WriteString(strings.TrimRight(generated, "\n"))
+	merged.WriteString("\n\n" + preservedHeader + "\n")
+	merged.WriteString(strings.Join(blocks, "\n\n"))
+	merged.WriteString("\n")
+
+	return merged.String(), preserved
+}
@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/config"
+)
+
+func (b *Builder) writePHPBuildTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	framework := ""
+	if cfg.Framework != nil {
+		framework = cfg.Framework.Name
+	}
+
+	fmt.Fprintf(w, "install:\n")
+	fmt.Fprintf(w, "\t$(COMPOSER) install\n")
+	fmt.Fprintf(w, ".PHONY: install\n\n")
+
+	fmt.Fprintf(w, "update:\n")
+	fmt.Fprintf(w, "\t$(COMPOSER) update\n")
+	fmt.Fprintf(w, ".PHONY: update\n\n")
+
+	fmt.Fprintf(w, "run:\n")
+	switch framework {
+	case "Laravel":
+		fmt.Fprintf(w, "\t$(PHP) artisan serve --port=$(PORT)\n")
+	case "Symfony":
+		fmt.Fprintf(w, "\t$(PHP) -S localhost:$(PORT) -t public\n")
+	default:
+		docroot := "."
+		if strings.HasPrefix(cfg.Entrypoint, "public/") {
+			docroot = "public"
+		}
+		fmt.Fprintf(w, "\t$(PHP) -S localhost:$(PORT) -t %s\n", docroot)
+	}
+	fmt.Fprintf(w, ".PHONY: run\n\n")
+
+	switch framework {
+	case "Laravel":
+		fmt.Fprintf(w, "cache-clear:\n")
+		fmt.Fprintf(w, "\t$(PHP) artisan optimize:clear\n")
+		fmt.Fprintf(w, ".PHONY: cache-clear\n\n")
+	case "Symfony":
+		fmt.Fprintf(w, "cache-clear:\n")
+		fmt.Fprintf(w, "\t$(PHP) bin/console cache:clear\n")
+		fmt.Fprintf(w, ".PHONY: cache-clear\n\n")
+	}
+
+	fmt.Fprintf(w, "test:\n")
+	if framework == "Laravel" {
+		fmt.Fprintf(w, "\t$(PHP) artisan test\n")
+	} else {
+		fmt.Fprintf(w, "\tvendor/bin/phpunit\n")
+	}
+	fmt.Fprintf(w, ".PHONY: test\n\n")
+}
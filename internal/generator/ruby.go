@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/config"
+)
+
+func (b *Builder) writeRubyBuildTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	rails := cfg.Framework != nil && cfg.Framework.Name == "Rails"
+
+	fmt.Fprintf(w, "install:\n")
+	fmt.Fprintf(w, "\t$(BUNDLE) install\n")
+	fmt.Fprintf(w, ".PHONY: install\n\n")
+
+	fmt.Fprintf(w, "run:\n")
+	switch {
+	case rails:
+		fmt.Fprintf(w, "\tbin/rails server -p $(PORT)\n")
+	case cfg.Entrypoint == "" || cfg.Entrypoint == "config.ru":
+		fmt.Fprintf(w, "\t$(BUNDLE) exec rackup -p $(PORT)\n")
+	default:
+		fmt.Fprintf(w, "\t$(BUNDLE) exec ruby %s\n", cfg.Entrypoint)
+	}
+	fmt.Fprintf(w, ".PHONY: run\n\n")
+
+	if rails {
+		fmt.Fprintf(w, "console:\n")
+		fmt.Fprintf(w, "\tbin/rails console\n")
+		fmt.Fprintf(w, ".PHONY: console\n\n")
+	}
+
+	// RSpec suites live in spec/, everything else goes through rake
+	fmt.Fprintf(w, "test:\n")
+	switch cfg.TestFramework {
+	case "rspec":
+		fmt.Fprintf(w, "\t$(BUNDLE) exec rspec\n")
+	case "minitest":
+		fmt.Fprintf(w, "\t$(RAKE) test\n")
+	default:
+		fmt.Fprintf(w, "\t$(if $(wildcard .rspec spec),$(BUNDLE) exec rspec,$(RAKE) test)\n")
+	}
+	fmt.Fprintf(w, ".PHONY: test\n\n")
+
+	fmt.Fprintf(w, "clean:\n")
+	fmt.Fprintf(w, "\trm -rf tmp/cache coverage\n")
+	fmt.Fprintf(w, ".PHONY: clean\n\n")
+}
@@ -0,0 +1,111 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/config"
+)
+
+// scanner is a security tool run only when installed
+type scanner struct {
+	bin     string // executable looked up on PATH
+	cmd     string
+	install string // hint printed when the tool is missing
+}
+
+// auditScanner returns the dependency audit tool of the language
+func auditScanner(cfg *config.MakefileConfig) (scanner, bool) {
+	switch cfg.Language {
+	case "go":
+		cmd := "govulncheck ./..."
+		if len(cfg.Modules) > 0 {
+			cmd = "for mod in $(GO_MODULES); do (cd $$mod && govulncheck ./...) || exit 1; done"
+		}
+		return scanner{bin: "govulncheck", cmd: cmd, install: "go install golang.org/x/vuln/cmd/govulncheck@latest"}, true
+	case "javascript", "typescript":
+		pm := cfg.PackageManager
+		if pm == "" {
+			pm = "npm"
+		}
+		return scanner{bin: pm, cmd: "$(NPM) audit", install: "https://nodejs.org"}, true
+	case "python":
+		return scanner{
+			bin:     "pip-audit",
+			cmd:     "pip-audit $(if $(wildcard requirements.txt),-r requirements.txt)",
+			install: "pipx install pip-audit",
+		}, true
+	case "rust":
+		return scanner{bin: "cargo-audit", cmd: "cargo audit", install: "cargo install cargo-audit"}, true
+	case "ruby":
+		return scanner{bin: "bundle-audit", cmd: "bundle-audit check --update", install: "gem install bundler-audit"}, true
+	}
+	return scanner{}, false
+}
+
+// sbomScanners lists the SBOM generators to try, in order of preference
+func sbomScanners(cfg *config.MakefileConfig) []scanner {
+	syft := scanner{bin: "syft", cmd: "syft dir:. -o cyclonedx-json=$(SBOM_FILE)", install: "https://github.com/anchore/syft#installation"}
+	if cfg.Language == "go" {
+		return []scanner{
+			{bin: "cyclonedx-gomod", cmd: "cyclonedx-gomod mod -json -output $(SBOM_FILE)", install: "go install github.com/CycloneDX/cyclonedx-gomod/cmd/cyclonedx-gomod@latest"},
+			syft,
+		}
+	}
+	return []scanner{syft}
+}
+
+var secretsScanner = scanner{
+	bin:     "gitleaks",
+	cmd:     "gitleaks detect --source . --no-banner --redact",
+	install: "https://github.com/gitleaks/gitleaks#installing",
+}
+
+// writeScannerRecipe runs the first installed scanner, or explains which
+// tool is missing and how to get it without failing the target
+func writeScannerRecipe(w *strings.Builder, cfg *config.MakefileConfig, scanners []scanner) {
+	var names, hints []string
+	for i, s := range scanners {
+		path := s.bin
+		if isPinned(cfg, s.bin) {
+			path = "$(TOOLS_DIR)/" + s.bin
+			s.cmd = pinCommand(cfg, s.cmd)
+			s.install = "run 'make tools'"
+		}
+		keyword := "elif"
+		if i == 0 {
+			keyword = "@if"
+		}
+		fmt.Fprintf(w, "\t%s command -v %s >/dev/null 2>&1; then \\\n", keyword, path)
+		fmt.Fprintf(w, "\t\t%s; \\\n", s.cmd)
+		names = append(names, s.bin)
+		hints = append(hints, s.install)
+	}
+	fmt.Fprintf(w, "\telse \\\n")
+	fmt.Fprintf(w, "\t\techo \"%s not installed, skipping (%s)\"; \\\n", strings.Join(names, " or "), strings.Join(hints, " or "))
+	fmt.Fprintf(w, "\tfi\n")
+}
+
+func (b *Builder) writeSecurityTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	fmt.Fprintf(w, "# Security Targets\n")
+
+	deps := []string{"secrets-scan"}
+	if audit, ok := auditScanner(cfg); ok {
+		deps = append([]string{"audit"}, deps...)
+
+		fmt.Fprintf(w, "audit:\n")
+		writeScannerRecipe(w, cfg, []scanner{audit})
+		fmt.Fprintf(w, ".PHONY: audit\n\n")
+	}
+
+	fmt.Fprintf(w, "sbom:\n")
+	writeScannerRecipe(w, cfg, sbomScanners(cfg))
+	fmt.Fprintf(w, ".PHONY: sbom\n\n")
+
+	fmt.Fprintf(w, "secrets-scan:\n")
+	writeScannerRecipe(w, cfg, []scanner{secretsScanner})
+	fmt.Fprintf(w, ".PHONY: secrets-scan\n\n")
+
+	fmt.Fprintf(w, "security: %s\n", strings.Join(deps, " "))
+	fmt.Fprintf(w, ".PHONY: security\n\n")
+}
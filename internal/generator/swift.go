@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/config"
+)
+
+func (b *Builder) writeSwiftBuildTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	fmt.Fprintf(w, "resolve:\n")
+	fmt.Fprintf(w, "\t$(SWIFT) package resolve\n")
+	fmt.Fprintf(w, ".PHONY: resolve\n\n")
+
+	fmt.Fprintf(w, "build:\n")
+	fmt.Fprintf(w, "\t$(SWIFT) build -c $(CONFIGURATION)\n")
+	fmt.Fprintf(w, ".PHONY: build\n\n")
+
+	fmt.Fprintf(w, "run:\n")
+	if cfg.Framework != nil && cfg.Framework.Name == "Vapor" {
+		fmt.Fprintf(w, "\t$(SWIFT) run -c $(CONFIGURATION) App serve --port $(PORT)\n")
+	} else {
+		fmt.Fprintf(w, "\t$(SWIFT) run -c $(CONFIGURATION)\n")
+	}
+	fmt.Fprintf(w, ".PHONY: run\n\n")
+
+	fmt.Fprintf(w, "test:\n")
+	fmt.Fprintf(w, "\t$(SWIFT) test\n")
+	fmt.Fprintf(w, ".PHONY: test\n\n")
+
+	fmt.Fprintf(w, "deps-update:\n")
+	fmt.Fprintf(w, "\t$(SWIFT) package update\n")
+	fmt.Fprintf(w, ".PHONY: deps-update\n\n")
+
+	fmt.Fprintf(w, "clean:\n")
+	fmt.Fprintf(w, "\t$(SWIFT) package clean\n")
+	fmt.Fprintf(w, ".PHONY: clean\n\n")
+}
@@ -0,0 +1,101 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/config"
+)
+
+// testCommands holds the recipes of the test-* targets for one runner.
+// An empty command means the runner has no equivalent.
+type testCommands struct {
+	test        string
+	unit        string // only written when integration tests are split out
+	integration string
+	watch       string
+	race        string
+}
+
+// testRunners maps a test framework to its commands. Frameworks whose
+// language already writes `test` with its build targets (rspec, minitest,
+// junit) are not listed.
+var testRunners = map[string]testCommands{
+	"go test": {
+		test:        "$(GO) test -v ./...",
+		unit:        "$(GO) test -short ./...",
+		integration: "$(GO) test -tags integration ./...",
+		race:        "$(GO) test -race ./...",
+	},
+	"vitest": {test: "npx vitest run", watch: "npx vitest"},
+	"jest":   {test: "$(NPM) test", watch: "npx jest --watch"},
+	"mocha":  {test: "npx mocha", watch: "npx mocha --watch"},
+	"pytest": {
+		test:        "$(PYTHON) -m pytest",
+		unit:        `$(PYTHON) -m pytest -m "not integration"`,
+		integration: "$(PYTHON) -m pytest -m integration",
+	},
+	"unittest": {test: "$(PYTHON) -m unittest discover"},
+	"cargo test": {
+		test:        "cargo test",
+		unit:        "cargo test --lib --bins",
+		integration: "cargo test --test '*'",
+	},
+	"cargo-nextest": {
+		test:        "cargo nextest run",
+		unit:        "cargo nextest run --lib --bins",
+		integration: "cargo nextest run --test '*'",
+	},
+}
+
+// e2eRunners maps an end-to-end framework to its command
+var e2eRunners = map[string]string{
+	"playwright": "npx playwright test",
+	"cypress":    "npx cypress run",
+}
+
+func (b *Builder) writeTestTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	runner, ok := testRunners[cfg.TestFramework]
+	e2e := e2eRunners[cfg.E2EFramework]
+	if !ok && e2e == "" {
+		return
+	}
+
+	fmt.Fprintf(w, "# Test Targets\n")
+
+	type testTarget struct {
+		name string
+		cmd  string
+	}
+	targets := []testTarget{
+		{"test", runner.test},
+		{"test-watch", runner.watch},
+		{"test-race", runner.race},
+	}
+	if cfg.IntegrationTests {
+		targets = append(targets, testTarget{"test-unit", runner.unit}, testTarget{"test-integration", runner.integration})
+	}
+
+	for _, target := range targets {
+		if target.cmd == "" {
+			continue
+		}
+		fmt.Fprintf(w, "%s:\n", target.name)
+		if cfg.Language == "go" {
+			fmt.Fprintf(w, "%s", goForEachModule(cfg, target.cmd))
+		} else {
+			fmt.Fprintf(w, "\t%s\n", target.cmd)
+		}
+		fmt.Fprintf(w, ".PHONY: %s\n\n", target.name)
+	}
+
+	if cfg.EnableCoverage {
+		b.writeCoverageTargets(w, cfg)
+	}
+
+	if e2e != "" {
+		fmt.Fprintf(w, "test-e2e:\n")
+		fmt.Fprintf(w, "\t%s\n", e2e)
+		fmt.Fprintf(w, ".PHONY: test-e2e\n\n")
+	}
+}
@@ -0,0 +1,187 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/config"
+)
+
+// pinnedTool is a tool `make tools` installs at a fixed version into
+// TOOLS_DIR. Recipes call it as invoke; once pinned, invoke is replaced by
+// $(TOOLS_DIR)/<pinned>.
+type pinnedTool struct {
+	bin       string
+	installer string // "go", "python", "npm" or "cargo"
+	pkg       string // module path, package or crate name
+	version   string
+	invoke    string
+	pinned    string
+}
+
+// pinnedTools lists every tool that can be bootstrapped, by executable name
+var pinnedTools = []pinnedTool{
+	{bin: "golangci-lint", installer: "go", pkg: "github.com/golangci/golangci-lint/cmd/golangci-lint", version: "v1.64.8", invoke: "golangci-lint", pinned: "golangci-lint"},
+	{bin: "govulncheck", installer: "go", pkg: "golang.org/x/vuln/cmd/govulncheck", version: "v1.1.4", invoke: "govulncheck", pinned: "govulncheck"},
+	{bin: "cyclonedx-gomod", installer: "go", pkg: "github.com/CycloneDX/cyclonedx-gomod/cmd/cyclonedx-gomod", version: "v1.9.0", invoke: "cyclonedx-gomod", pinned: "cyclonedx-gomod"},
+	{bin: "syft", installer: "go", pkg: "github.com/anchore/syft/cmd/syft", version: "v1.33.0", invoke: "syft", pinned: "syft"},
+	{bin: "gitleaks", installer: "go", pkg: "github.com/zricethezav/gitleaks/v8", version: "v8.28.0", invoke: "gitleaks", pinned: "gitleaks"},
+	{bin: "ruff", installer: "python", pkg: "ruff", version: "0.13.3", invoke: "$(PYTHON) -m ruff", pinned: "ruff"},
+	{bin: "black", installer: "python", pkg: "black", version: "25.9.0", invoke: "$(PYTHON) -m black", pinned: "black"},
+	{bin: "pylint", installer: "python", pkg: "pylint", version: "3.3.9", invoke: "$(PYTHON) -m pylint", pinned: "pylint"},
+	{bin: "mypy", installer: "python", pkg: "mypy", version: "1.18.2", invoke: "$(PYTHON) -m mypy", pinned: "mypy"},
+	{bin: "pip-audit", installer: "python", pkg: "pip-audit", version: "2.9.0", invoke: "pip-audit", pinned: "pip-audit"},
+	{bin: "eslint", installer: "npm", pkg: "eslint", version: "9.37.0", invoke: "npx eslint", pinned: "eslint"},
+	{bin: "prettier", installer: "npm", pkg: "prettier", version: "3.6.2", invoke: "npx prettier", pinned: "prettier"},
+	{bin: "cargo-audit", installer: "cargo", pkg: "cargo-audit", version: "0.21.2", invoke: "cargo audit", pinned: "cargo-audit audit"},
+}
+
+// golangciLintV2 replaces the v1 pin when the golangci config declares
+// version: "2"; each major version refuses the other's config
+var golangciLintV2 = pinnedTool{bin: "golangci-lint", installer: "go", pkg: "github.com/golangci/golangci-lint/v2/cmd/golangci-lint", version: "v2.5.0", invoke: "golangci-lint", pinned: "golangci-lint"}
+
+// lintToolBins maps lint/format tool names to the executable they run
+var lintToolBins = map[string]string{
+	"ruff-format": "ruff",
+}
+
+// referencedTools returns the pinnable tools the generated targets call
+func referencedTools(cfg *config.MakefileConfig) []pinnedTool {
+	wanted := make(map[string]bool)
+	for _, name := range append(append([]string{}, cfg.LintTools...), cfg.FormatTools...) {
+		if bin, ok := lintToolBins[name]; ok {
+			name = bin
+		}
+		wanted[name] = true
+	}
+
+	if cfg.EnableSecurity {
+		if audit, ok := auditScanner(cfg); ok {
+			wanted[audit.bin] = true
+		}
+		wanted[sbomScanners(cfg)[0].bin] = true
+		wanted[secretsScanner.bin] = true
+	}
+
+	var tools []pinnedTool
+	for _, tool := range pinnedTools {
+		if !wanted[tool.bin] {
+			continue
+		}
+		if tool.bin == golangciLintV2.bin && cfg.GolangciLintV2 {
+			tool = golangciLintV2
+		}
+		tools = append(tools, tool)
+	}
+	return tools
+}
+
+// isPinned reports whether a referenced tool is installed into TOOLS_DIR
+func isPinned(cfg *config.MakefileConfig, bin string) bool {
+	if !cfg.PinTools {
+		return false
+	}
+	for _, tool := range referencedTools(cfg) {
+		if tool.bin == bin {
+			return true
+		}
+	}
+	return false
+}
+
+// pinCommand makes a recipe call the pinned copies in TOOLS_DIR
+func pinCommand(cfg *config.MakefileConfig, cmd string) string {
+	if !cfg.PinTools {
+		return cmd
+	}
+	for _, tool := range referencedTools(cfg) {
+		cmd = strings.Replace(cmd, tool.invoke, "$(TOOLS_DIR)/"+tool.pinned, 1)
+	}
+	return cmd
+}
+
+// toolsPrereq makes a lint or format target install the pinned tools it
+// runs first, and only those
+func toolsPrereq(cfg *config.MakefileConfig, names []string) string {
+	if !cfg.PinTools {
+		return ""
+	}
+	bins := make(map[string]bool)
+	for _, name := range names {
+		if bin, ok := lintToolBins[name]; ok {
+			name = bin
+		}
+		bins[name] = true
+	}
+
+	var prereq string
+	for _, tool := range referencedTools(cfg) {
+		if bins[tool.bin] {
+			prereq += " " + toolStamp(tool)
+		}
+	}
+	return prereq
+}
+
+// toolVersionVar names the variable holding a tool's version
+func toolVersionVar(tool pinnedTool) string {
+	return strings.ToUpper(strings.ReplaceAll(tool.bin, "-", "_")) + "_VERSION"
+}
+
+// toolStamp is the file recording that a tool version is installed, so a
+// version bump reinstalls it
+func toolStamp(tool pinnedTool) string {
+	return fmt.Sprintf("$(TOOLS_DIR)/.versions/%s@$(%s)", tool.bin, toolVersionVar(tool))
+}
+
+func (b *Builder) writeToolsVariables(w *strings.Builder, cfg *config.MakefileConfig) {
+	tools := referencedTools(cfg)
+	if len(tools) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "TOOLS_DIR := $(CURDIR)/.bin\n")
+	for _, tool := range tools {
+		fmt.Fprintf(w, "%s ?= %s\n", toolVersionVar(tool), tool.version)
+	}
+}
+
+func (b *Builder) writeToolsTargets(w *strings.Builder, cfg *config.MakefileConfig) {
+	tools := referencedTools(cfg)
+	if len(tools) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "# Tool Targets\n")
+
+	var stamps []string
+	for _, tool := range tools {
+		stamps = append(stamps, toolStamp(tool))
+	}
+	fmt.Fprintf(w, "tools: %s\n", strings.Join(stamps, " "))
+	fmt.Fprintf(w, ".PHONY: tools\n\n")
+
+	for _, tool := range tools {
+		version := "$(" + toolVersionVar(tool) + ")"
+
+		fmt.Fprintf(w, "%s:\n", toolStamp(tool))
+		switch tool.installer {
+		case "go":
+			fmt.Fprintf(w, "\tGOBIN=$(TOOLS_DIR) go install %s@%s\n", tool.pkg, version)
+		case "python":
+			fmt.Fprintf(w, "\t@if command -v uv >/dev/null 2>&1; then \\\n")
+			fmt.Fprintf(w, "\t\tUV_TOOL_DIR=$(TOOLS_DIR)/.uv UV_TOOL_BIN_DIR=$(TOOLS_DIR) uv tool install --force %s==%s; \\\n", tool.pkg, version)
+			fmt.Fprintf(w, "\telse \\\n")
+			fmt.Fprintf(w, "\t\tPIPX_HOME=$(TOOLS_DIR)/.pipx PIPX_BIN_DIR=$(TOOLS_DIR) pipx install --force %s==%s; \\\n", tool.pkg, version)
+			fmt.Fprintf(w, "\tfi\n")
+		case "npm":
+			fmt.Fprintf(w, "\tnpm install --prefix $(TOOLS_DIR)/.npm/%s %s@%s\n", tool.bin, tool.pkg, version)
+			fmt.Fprintf(w, "\tln -sf $(TOOLS_DIR)/.npm/%s/node_modules/.bin/%s $(TOOLS_DIR)/%s\n", tool.bin, tool.bin, tool.bin)
+		case "cargo":
+			fmt.Fprintf(w, "\tcargo install --locked --root $(TOOLS_DIR)/.cargo %s --version %s\n", tool.pkg, version)
+			fmt.Fprintf(w, "\tln -sf $(TOOLS_DIR)/.cargo/bin/%s $(TOOLS_DIR)/%s\n", tool.bin, tool.bin)
+		}
+		fmt.Fprintf(w, "\t@rm -f $(TOOLS_DIR)/.versions/%s@*\n", tool.bin)
+		fmt.Fprintf(w, "\t@mkdir -p $(@D) && touch $@\n\n")
+	}
+}
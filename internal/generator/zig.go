@@ -0,0 +1,32 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (b *Builder) writeZigBuildTargets(w *strings.Builder) {
+	fmt.Fprintf(w, "build:\n")
+	fmt.Fprintf(w, "\t$(ZIG) build -Doptimize=$(OPTIMIZE)\n")
+	fmt.Fprintf(w, ".PHONY: build\n\n")
+
+	fmt.Fprintf(w, "run:\n")
+	fmt.Fprintf(w, "\t$(ZIG) build run -Doptimize=$(OPTIMIZE)\n")
+	fmt.Fprintf(w, ".PHONY: run\n\n")
+
+	fmt.Fprintf(w, "test:\n")
+	fmt.Fprintf(w, "\t$(ZIG) build test\n")
+	fmt.Fprintf(w, ".PHONY: test\n\n")
+
+	fmt.Fprintf(w, "format:\n")
+	fmt.Fprintf(w, "\t$(ZIG) fmt .\n")
+	fmt.Fprintf(w, ".PHONY: format\n\n")
+
+	fmt.Fprintf(w, "format-check:\n")
+	fmt.Fprintf(w, "\t$(ZIG) fmt --check .\n")
+	fmt.Fprintf(w, ".PHONY: format-check\n\n")
+
+	fmt.Fprintf(w, "clean:\n")
+	fmt.Fprintf(w, "\trm -rf zig-out .zig-cache zig-cache\n")
+	fmt.Fprintf(w, ".PHONY: clean\n\n")
+}
@@ -1,9 +1,14 @@
 package storage
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/gaoubak/Makegen/internal/utils"
 )
@@ -12,6 +17,12 @@ import (
 type FileSystem interface {
 	WriteMakefile(dir, content string) error
 	ReadMakefile(dir string) (string, error)
+	WriteFile(dir, path, content string) error
+	ReadFile(path string) (string, error)
+	ListBackups(dir string) ([]Backup, error)
+	RestoreBackup(dir string, backup Backup) error
+	SaveState(dir, path string, state any) error
+	LoadState(dir, path string, state any) error
 	FileExists(path string) bool
 	ListFiles(dir string, extensions []string) ([]string, error)
 }
@@ -30,35 +41,74 @@ func NewLocalFileSystem(logger *utils.Logger) *LocalFileSystem {
 
 // WriteMakefile writes the Makefile to disk
 func (lfs *LocalFileSystem) WriteMakefile(dir, content string) error {
-	makefilePath := filepath.Join(dir, "Makefile")
+	return lfs.WriteFile(dir, filepath.Join(dir, "Makefile"), content)
+}
+
+// ReadMakefile reads an existing Makefile
+func (lfs *LocalFileSystem) ReadMakefile(dir string) (string, error) {
+	return lfs.ReadFile(filepath.Join(dir, "Makefile"))
+}
+
+// WriteFile writes generated content to any path: a Makefile, a
+// GNUmakefile or an include fragment. The content goes to a temporary file
+// that is synced and renamed over the target, so an interrupted write never
+// leaves a truncated file. The previous version is kept as a backup in the
+// project directory dir, whatever directory path is in.
+func (lfs *LocalFileSystem) WriteFile(dir, path, content string) error {
+	name := filepath.Base(path)
 
-	// Check if file exists
-	if lfs.FileExists(makefilePath) {
-		lfs.logger.Warn("Makefile already exists at %s", makefilePath)
-		lfs.logger.Warn("It will be overwritten")
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+
+		backup, err := lfs.backup(dir, path)
+		if err != nil {
+			return fmt.Errorf("failed to back up %s: %w", name, err)
+		}
+		lfs.logger.Info("Previous %s saved to %s", name, backup)
 	}
 
-	// Write file
-	err := os.WriteFile(makefilePath, []byte(content), 0644)
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+name+".tmp-*")
 	if err != nil {
-		return fmt.Errorf("failed to write Makefile: %w", err)
+		return fmt.Errorf("failed to write %s: %w", name, err)
 	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
 
-	lfs.logger.Info("Makefile written to %s", makefilePath)
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	syncDir(filepath.Dir(path))
+
+	lfs.logger.Info("%s written to %s", name, path)
 	return nil
 }
 
-// ReadMakefile reads an existing Makefile
-func (lfs *LocalFileSystem) ReadMakefile(dir string) (string, error) {
-	makefilePath := filepath.Join(dir, "Makefile")
+// ReadFile reads an existing Makefile or fragment
+func (lfs *LocalFileSystem) ReadFile(path string) (string, error) {
+	name := filepath.Base(path)
 
-	if !lfs.FileExists(makefilePath) {
-		return "", fmt.Errorf("Makefile not found at %s", makefilePath)
+	if !lfs.FileExists(path) {
+		return "", fmt.Errorf("%s not found at %s", name, path)
 	}
 
-	content, err := os.ReadFile(makefilePath)
+	content, err := os.ReadFile(path)
 	if err != nil {
-		return "", fmt.Errorf("failed to read Makefile: %w", err)
+		return "", fmt.Errorf("failed to read %s: %w", name, err)
 	}
 
 	return string(content), nil
@@ -96,3 +146,172 @@ func (lfs *LocalFileSystem) ListFiles(dir string, extensions []string) ([]string
 
 	return files, nil
 }
+
+// BackupDir is where WriteFile keeps previous versions, relative to the
+// project directory
+const BackupDir = ".makegen/backups"
+
+// backupTimeFormat sorts backups chronologically by name
+const backupTimeFormat = "20060102-150405.000000"
+
+// Backup is a previous version of a file saved by WriteFile. Its file name
+// is "<timestamp>_<target>", with the target path-escaped so that files in
+// subdirectories or outside the project keep their location.
+type Backup struct {
+	Path   string
+	Target string // file it is a copy of, relative to the project directory when inside it
+	Time   time.Time
+}
+
+// backup copies the current file into the BackupDir of the project
+// directory dir and returns the copy's path
+func (lfs *LocalFileSystem) backup(dir, path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := relTarget(dir, path)
+	if err != nil {
+		return "", err
+	}
+
+	backups := filepath.Join(dir, BackupDir)
+	if err := os.MkdirAll(backups, 0755); err != nil {
+		return "", err
+	}
+
+	backup := filepath.Join(backups, time.Now().Format(backupTimeFormat)+"_"+url.PathEscape(filepath.ToSlash(target)))
+	if err := os.WriteFile(backup, content, 0644); err != nil {
+		return "", err
+	}
+	return backup, nil
+}
+
+// ListBackups returns the backups kept for files in dir, newest first
+func (lfs *LocalFileSystem) ListBackups(dir string) ([]Backup, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, BackupDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	var backups []Backup
+	for _, entry := range entries {
+		stamp, escaped, ok := strings.Cut(entry.Name(), "_")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		target, err := url.PathUnescape(escaped)
+		if err != nil {
+			continue
+		}
+		when, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{
+			Path:   filepath.Join(dir, BackupDir, entry.Name()),
+			Target: filepath.FromSlash(target),
+			Time:   when,
+		})
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].Time.After(backups[j].Time)
+	})
+	return backups, nil
+}
+
+// RestoreBackup writes a backup back over its file in the project
+// directory dir. The version it replaces is backed up in turn, so a restore
+// can be undone.
+func (lfs *LocalFileSystem) RestoreBackup(dir string, backup Backup) error {
+	content, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	path := backup.Target
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return lfs.WriteFile(dir, path, string(content))
+}
+
+// StateDir is where SaveState keeps what makegen remembers about the files
+// it wrote, relative to the project directory
+const StateDir = ".makegen/state"
+
+// SaveState stores state as JSON for the file at path, in the StateDir of
+// the project directory dir
+func (lfs *LocalFileSystem) SaveState(dir, path string, state any) error {
+	file, err := statePath(dir, path)
+	if err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	if err := os.WriteFile(file, append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
+
+// LoadState reads the state SaveState stored for path into state. The
+// error wraps fs.ErrNotExist when nothing was stored.
+func (lfs *LocalFileSystem) LoadState(dir, path string, state any) error {
+	file, err := statePath(dir, path)
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	if err := json.Unmarshal(content, state); err != nil {
+		return fmt.Errorf("failed to load state from %s: %w", file, err)
+	}
+	return nil
+}
+
+// statePath is the file holding the state of path, named after it like a
+// backup
+func statePath(dir, path string) (string, error) {
+	target, err := relTarget(dir, path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, StateDir, url.PathEscape(filepath.ToSlash(target))+".json"), nil
+}
+
+// relTarget is path relative to the project directory dir when it is
+// inside it, and absolute otherwise
+func relTarget(dir, path string) (string, error) {
+	target, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if root, err := filepath.Abs(dir); err == nil {
+		if rel, err := filepath.Rel(root, target); err == nil && !strings.HasPrefix(rel, "..") {
+			target = rel
+		}
+	}
+	return target, nil
+}
+
+// syncDir flushes a rename to disk. Not every platform can sync a
+// directory, so failures are ignored.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
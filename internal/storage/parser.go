@@ -1,3 +1,204 @@
 package storage
 
-// TODO: Implement Makefile parsing
+import (
+	"regexp"
+	"strings"
+)
+
+// Block kinds found in a Makefile
+const (
+	BlockRule      = "rule"
+	BlockVariable  = "variable"
+	BlockDirective = "directive" // include, conditionals, define, export...
+	BlockComment   = "comment"
+)
+
+var (
+	variableRe = regexp.MustCompile(`^(?:export\s+|override\s+)?([A-Za-z_][A-Za-z0-9_.-]*)\s*(?::{1,3}=|\?=|\+=|!=|=)`)
+	ruleRe     = regexp.MustCompile(`^([^\s:=#][^:=#]*?)\s*::?(?:[^=]|$)`)
+)
+
+// Block is a top-level construct of a Makefile together with the comment
+// lines directly above it
+type Block struct {
+	Kind    string
+	Names   []string // targets of a rule, or the variable name
+	Prereqs []string
+	Text    string
+}
+
+// ParsedMakefile is an existing Makefile split into blocks
+type ParsedMakefile struct {
+	Blocks []Block
+	Phony  map[string]bool
+}
+
+// ParseMakefile splits Makefile content into rules, variables and
+// directives. It is not a full make parser, just enough to tell which
+// targets and variables a file defines so they can be preserved.
+func ParseMakefile(content string) *ParsedMakefile {
+	parsed := &ParsedMakefile{Phony: make(map[string]bool)}
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	var comments []string
+	flushComments := func() {
+		if len(comments) > 0 {
+			parsed.Blocks = append(parsed.Blocks, Block{Kind: BlockComment, Text: strings.Join(comments, "\n")})
+			comments = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flushComments()
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			comments = append(comments, line)
+			continue
+		}
+
+		start := i
+		block := Block{}
+
+		switch {
+		case isConditional(trimmed) || strings.HasPrefix(trimmed, "define "):
+			block.Kind = BlockDirective
+			i = endOfDirective(lines, i)
+			if name, ok := strings.CutPrefix(trimmed, "define "); ok {
+				block.Names = strings.Fields(name)[:1]
+			}
+
+		case variableRe.MatchString(line) && !strings.HasPrefix(line, "\t"):
+			block.Kind = BlockVariable
+			block.Names = []string{variableRe.FindStringSubmatch(line)[1]}
+			i = endOfContinuation(lines, i)
+
+		case ruleRe.MatchString(line) && !strings.HasPrefix(line, "\t"):
+			header := lines[i]
+			i = endOfContinuation(lines, i)
+			for j := start + 1; j <= i; j++ {
+				header += "\n" + lines[j]
+			}
+			targets, prereqs, _ := strings.Cut(strings.ReplaceAll(header, "\\\n", " "), ":")
+			prereqs = strings.TrimPrefix(prereqs, ":")
+			if before, _, ok := strings.Cut(prereqs, ";"); ok {
+				prereqs = before
+			}
+			block.Kind = BlockRule
+			block.Names = strings.Fields(targets)
+			block.Prereqs = strings.Fields(strings.Split(prereqs, "|")[0])
+
+			// Recipe lines start with a tab; blank lines between them
+			// belong to the recipe
+			for i+1 < len(lines) && isRecipeLine(lines, i+1) {
+				i++
+			}
+
+			if len(block.Names) == 1 && block.Names[0] == ".PHONY" {
+				for _, name := range block.Prereqs {
+					parsed.Phony[name] = true
+				}
+			}
+
+		default:
+			block.Kind = BlockDirective
+			i = endOfContinuation(lines, i)
+		}
+
+		text := strings.Join(lines[start:i+1], "\n")
+		if len(comments) > 0 {
+			text = strings.Join(comments, "\n") + "\n" + text
+			comments = nil
+		}
+		block.Text = strings.TrimRight(text, "\n")
+		parsed.Blocks = append(parsed.Blocks, block)
+	}
+	flushComments()
+
+	return parsed
+}
+
+// Targets returns every target defined by a rule, special targets excluded
+func (p *ParsedMakefile) Targets() []string {
+	var targets []string
+	for _, block := range p.Blocks {
+		if block.Kind != BlockRule {
+			continue
+		}
+		for _, name := range block.Names {
+			if !strings.HasPrefix(name, ".") {
+				targets = append(targets, name)
+			}
+		}
+	}
+	return targets
+}
+
+// Variables returns the names of all variables assigned at top level
+func (p *ParsedMakefile) Variables() []string {
+	var names []string
+	for _, block := range p.Blocks {
+		if block.Kind == BlockVariable || (block.Kind == BlockDirective && len(block.Names) > 0) {
+			names = append(names, block.Names...)
+		}
+	}
+	return names
+}
+
+func isConditional(line string) bool {
+	for _, keyword := range []string{"ifeq", "ifneq", "ifdef", "ifndef"} {
+		if line == keyword || strings.HasPrefix(line, keyword+" ") || strings.HasPrefix(line, keyword+"(") {
+			return true
+		}
+	}
+	return false
+}
+
+// endOfDirective returns the line closing a conditional or define block
+func endOfDirective(lines []string, start int) int {
+	depth := 0
+	for i := start; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		switch {
+		case isConditional(trimmed) || strings.HasPrefix(trimmed, "define "):
+			depth++
+		case trimmed == "endif" || trimmed == "endef" ||
+			strings.HasPrefix(trimmed, "endif ") || strings.HasPrefix(trimmed, "endef "):
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(lines) - 1
+}
+
+// endOfContinuation returns the last line of a backslash-continued line
+func endOfContinuation(lines []string, start int) int {
+	i := start
+	for strings.HasSuffix(lines[i], "\\") && i+1 < len(lines) {
+		i++
+	}
+	return i
+}
+
+// isRecipeLine reports whether line i still belongs to the current recipe
+func isRecipeLine(lines []string, i int) bool {
+	if strings.HasPrefix(lines[i], "\t") {
+		return true
+	}
+	// A blank line is part of the recipe only if the recipe continues
+	if strings.TrimSpace(lines[i]) == "" {
+		for j := i + 1; j < len(lines); j++ {
+			if strings.TrimSpace(lines[j]) == "" {
+				continue
+			}
+			return strings.HasPrefix(lines[j], "\t")
+		}
+	}
+	return false
+}
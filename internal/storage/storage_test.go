@@ -1,7 +1,171 @@
 package storage
 
-import "testing"
+import (
+	"errors"
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
 
-func TestStorage(t *testing.T) {
-	t.Skip("Implementation pending")
+	"github.com/gaoubak/Makegen/internal/utils"
+)
+
+const sampleMakefile = `# Generated Makefile
+PROJECT_NAME := demo
+CFLAGS = -O2 \
+	-Wall
+
+ifeq ($(OS),Windows_NT)
+EXE := .exe
+endif
+
+build:
+	go build ./...
+.PHONY: build
+
+# Regenerate fixtures
+fixtures: testdata/in.json
+	./scripts/fixtures.sh
+
+	@echo done
+.PHONY: fixtures
+`
+
+func TestParseMakefile(t *testing.T) {
+	parsed := ParseMakefile(sampleMakefile)
+
+	if want := []string{"build", "fixtures"}; !reflect.DeepEqual(parsed.Targets(), want) {
+		t.Errorf("Targets() = %v, want %v", parsed.Targets(), want)
+	}
+	if want := []string{"PROJECT_NAME", "CFLAGS"}; !reflect.DeepEqual(parsed.Variables(), want) {
+		t.Errorf("Variables() = %v, want %v", parsed.Variables(), want)
+	}
+	if !parsed.Phony["build"] || !parsed.Phony["fixtures"] {
+		t.Errorf("Phony = %v", parsed.Phony)
+	}
+
+	for _, block := range parsed.Blocks {
+		if block.Kind == BlockRule && block.Names[0] == "fixtures" {
+			want := "# Regenerate fixtures\nfixtures: testdata/in.json\n\t./scripts/fixtures.sh\n\n\t@echo done"
+			if block.Text != want {
+				t.Errorf("fixtures block = %q, want %q", block.Text, want)
+			}
+			if !reflect.DeepEqual(block.Prereqs, []string{"testdata/in.json"}) {
+				t.Errorf("fixtures prereqs = %v", block.Prereqs)
+			}
+		}
+	}
+}
+
+func TestWriteFileBackupAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Makefile")
+	fs := NewLocalFileSystem(utils.NewLogger(false))
+
+	if err := os.WriteFile(path, []byte("old:\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.WriteFile(dir, path, "new:\n"); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("WriteFile() mode = %v, want 0600 kept", info.Mode().Perm())
+	}
+	if content, _ := fs.ReadFile(path); content != "new:\n" {
+		t.Errorf("WriteFile() content = %q", content)
+	}
+
+	// Only the backup is left next to the file, no temporary files
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("WriteFile() left %d entries in %s, want Makefile and .makegen", len(entries), dir)
+	}
+
+	backups, err := fs.ListBackups(dir)
+	if err != nil || len(backups) != 1 || backups[0].Target != "Makefile" {
+		t.Fatalf("ListBackups() = %+v, %v", backups, err)
+	}
+
+	if err := fs.RestoreBackup(dir, backups[0]); err != nil {
+		t.Fatalf("RestoreBackup() error = %v", err)
+	}
+	if content, _ := fs.ReadFile(path); content != "old:\n" {
+		t.Errorf("RestoreBackup() content = %q", content)
+	}
+
+	backups, _ = fs.ListBackups(dir)
+	if len(backups) != 2 {
+		t.Fatalf("RestoreBackup() should back up the replaced version, got %d backups", len(backups))
+	}
+	if content, _ := os.ReadFile(backups[0].Path); string(content) != "new:\n" {
+		t.Errorf("newest backup = %q, want the version replaced by the restore", content)
+	}
+}
+
+func TestBackupsKeptInProjectDirectory(t *testing.T) {
+	dir := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "out.mk")
+	sub := filepath.Join(dir, "sub", "makegen.mk")
+	fs := NewLocalFileSystem(utils.NewLogger(false))
+
+	for _, path := range []string{sub, outside} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("old:\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := fs.WriteFile(dir, path, "new:\n"); err != nil {
+			t.Fatalf("WriteFile(%s) error = %v", path, err)
+		}
+	}
+
+	backups, err := fs.ListBackups(dir)
+	if err != nil || len(backups) != 2 {
+		t.Fatalf("ListBackups() = %+v, %v", backups, err)
+	}
+	targets := map[string]bool{backups[0].Target: true, backups[1].Target: true}
+	if !targets[filepath.Join("sub", "makegen.mk")] || !targets[outside] {
+		t.Errorf("backup targets = %v, want sub/makegen.mk and %s", targets, outside)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sub", BackupDir)); !os.IsNotExist(err) {
+		t.Error("backup written next to the file instead of the project directory")
+	}
+
+	for _, backup := range backups {
+		if err := fs.RestoreBackup(dir, backup); err != nil {
+			t.Fatalf("RestoreBackup() error = %v", err)
+		}
+	}
+	for _, path := range []string{sub, outside} {
+		if content, _ := fs.ReadFile(path); content != "old:\n" {
+			t.Errorf("%s restored as %q, want old:", path, content)
+		}
+	}
+}
+
+func TestSaveLoadState(t *testing.T) {
+	dir := t.TempDir()
+	fs := NewLocalFileSystem(utils.NewLogger(false))
+	path := filepath.Join(dir, "sub", "makegen.mk")
+
+	var state map[string]string
+	if err := fs.LoadState(dir, path, &state); !errors.Is(err, iofs.ErrNotExist) {
+		t.Fatalf("LoadState() before SaveState error = %v, want ErrNotExist", err)
+	}
+	if err := fs.SaveState(dir, path, map[string]string{"name": "demo"}); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	if err := fs.LoadState(dir, path, &state); err != nil || state["name"] != "demo" {
+		t.Fatalf("LoadState() = %v, %v", state, err)
+	}
+	if err := fs.LoadState(dir, filepath.Join(dir, "Makefile"), &state); !errors.Is(err, iofs.ErrNotExist) {
+		t.Errorf("LoadState() of another file error = %v, want ErrNotExist", err)
+	}
 }
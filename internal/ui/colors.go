@@ -1,5 +1,10 @@
 package ui
 
+import (
+	"io"
+	"os"
+)
+
 // Color definitions for terminal output
 const (
 	ColorReset   = "\033[0m"
@@ -16,3 +21,22 @@ const (
 func Colorize(text string, color string) string {
 	return color + text + ColorReset
 }
+
+// ColorEnabled reports whether output to w should be colored: only
+// terminals are, and never when NO_COLOR is set or TERM is dumb
+func ColorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return IsTerminal(w)
+}
+
+// IsTerminal reports whether w is a character device such as a terminal
+func IsTerminal(w interface{}) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -1,3 +1,48 @@
 package ui
 
-// TODO: Implement output formatting
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/utils"
+)
+
+// FormatDiff renders hunks as a unified diff, colored when color is set
+func FormatDiff(oldName, newName string, hunks []utils.DiffHunk, color bool) string {
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(paint(fmt.Sprintf("--- %s\n+++ %s", oldName, newName), ColorYellow, color) + "\n")
+	for _, hunk := range hunks {
+		b.WriteString(FormatHunk(hunk, color))
+	}
+	return b.String()
+}
+
+// FormatHunk renders one hunk: the @@ header, then removed lines in red and
+// added lines in green
+func FormatHunk(hunk utils.DiffHunk, color bool) string {
+	var b strings.Builder
+	b.WriteString(paint(hunk.Header(), ColorCyan, color) + "\n")
+	for _, line := range hunk.Lines {
+		text := string(line.Kind) + line.Text
+		switch line.Kind {
+		case '-':
+			text = paint(text, ColorRed, color)
+		case '+':
+			text = paint(text, ColorGreen, color)
+		}
+		b.WriteString(text + "\n")
+	}
+	return b.String()
+}
+
+// paint colors text when color is set
+func paint(text, color string, enabled bool) string {
+	if !enabled {
+		return text
+	}
+	return Colorize(text, color)
+}
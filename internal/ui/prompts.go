@@ -1,3 +1,212 @@
 package ui
 
-// TODO: Implement prompt functions
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/utils"
+)
+
+// Prompter asks the questions of the questionnaire. Each method returns
+// the default when input ends.
+type Prompter interface {
+	// Confirm asks a yes/no question
+	Confirm(message string, defaultYes bool) bool
+	// Input asks for a line of text, re-asking while validate (if not
+	// nil) rejects the answer. An empty answer selects defaultValue.
+	Input(message, defaultValue string, validate func(string) error) string
+	// Select picks one of options
+	Select(message string, options []string, defaultIndex int) int
+	// MultiSelect toggles any of options, starting from selected
+	MultiSelect(message string, options []string, selected []bool) []bool
+}
+
+// NewPrompter returns arrow-key widgets when in and out are a terminal,
+// and line prompts otherwise
+func NewPrompter(in io.Reader, out io.Writer) Prompter {
+	if f, ok := in.(*os.File); ok && IsTerminal(f) && IsTerminal(out) {
+		if _, err := stty(f, "-g"); err == nil {
+			return newTTYPrompter(f, out)
+		}
+	}
+	return NewLinePrompter(in, out)
+}
+
+// LinePrompter asks questions one line at a time, for pipes, CI logs and
+// terminals without raw mode
+type LinePrompter struct {
+	reader *bufio.Reader
+	out    io.Writer
+	color  bool
+}
+
+// NewLinePrompter creates a line prompter
+func NewLinePrompter(in io.Reader, out io.Writer) *LinePrompter {
+	return &LinePrompter{reader: bufio.NewReader(in), out: out, color: ColorEnabled(out)}
+}
+
+// readLine reads a trimmed answer; ok is false once input has ended
+func (p *LinePrompter) readLine() (string, bool) {
+	line, err := p.reader.ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(p.out)
+		return "", false
+	}
+	return strings.TrimSpace(line), true
+}
+
+// Confirm asks a yes/no question
+func (p *LinePrompter) Confirm(message string, defaultYes bool) bool {
+	suffix := "[Y/n]"
+	if !defaultYes {
+		suffix = "[y/N]"
+	}
+	fmt.Fprintf(p.out, "%s %s: ", message, suffix)
+
+	answer, _ := p.readLine()
+	switch strings.ToLower(answer) {
+	case "":
+		return defaultYes
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// Input asks for a line of text
+func (p *LinePrompter) Input(message, defaultValue string, validate func(string) error) string {
+	for {
+		if defaultValue != "" {
+			fmt.Fprintf(p.out, "%s [%s]: ", message, defaultValue)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", message)
+		}
+
+		answer, ok := p.readLine()
+		if answer == "" {
+			answer = defaultValue
+		}
+		if !ok || validate == nil {
+			return answer
+		}
+		if err := validate(answer); err != nil {
+			fmt.Fprintf(p.out, "%s\n", paint("  ✗ "+err.Error(), ColorRed, p.color))
+			continue
+		}
+		return answer
+	}
+}
+
+// Select lists the numbered options and reads the chosen number
+func (p *LinePrompter) Select(message string, options []string, defaultIndex int) int {
+	fmt.Fprintf(p.out, "%s\n", message)
+	for i, option := range options {
+		fmt.Fprintf(p.out, "  %d. %s\n", i+1, option)
+	}
+
+	for {
+		fmt.Fprintf(p.out, "Select [1-%d] (default %d): ", len(options), defaultIndex+1)
+		answer, ok := p.readLine()
+		if answer == "" || !ok {
+			return defaultIndex
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return n - 1
+		}
+		fmt.Fprintf(p.out, "%s\n", paint(fmt.Sprintf("  ✗ enter a number from 1 to %d", len(options)), ColorRed, p.color))
+	}
+}
+
+// MultiSelect lists the options with their state and reads the numbers to
+// select: Enter keeps the current selection and "none" clears it
+func (p *LinePrompter) MultiSelect(message string, options []string, selected []bool) []bool {
+	fmt.Fprintf(p.out, "%s\n", message)
+	for i, option := range options {
+		mark := " "
+		if selected[i] {
+			mark = "x"
+		}
+		fmt.Fprintf(p.out, "  %d. [%s] %s\n", i+1, mark, option)
+	}
+
+	for {
+		fmt.Fprintf(p.out, "Numbers to select, separated by spaces (Enter keeps, \"none\" clears): ")
+		answer, ok := p.readLine()
+		if answer == "" || !ok {
+			return selected
+		}
+
+		chosen := make([]bool, len(options))
+		if answer == "none" {
+			return chosen
+		}
+		valid := true
+		for _, field := range strings.FieldsFunc(answer, func(r rune) bool { return r == ' ' || r == ',' }) {
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 1 || n > len(options) {
+				valid = false
+				break
+			}
+			chosen[n-1] = true
+		}
+		if valid {
+			return chosen
+		}
+		fmt.Fprintf(p.out, "%s\n", paint(fmt.Sprintf("  ✗ enter numbers from 1 to %d", len(options)), ColorRed, p.color))
+	}
+}
+
+// defaultPrompter answers every question with its default
+type defaultPrompter struct{}
+
+func (defaultPrompter) Confirm(_ string, defaultYes bool) bool { return defaultYes }
+
+func (defaultPrompter) Input(_, defaultValue string, _ func(string) error) string {
+	return defaultValue
+}
+
+func (defaultPrompter) Select(_ string, _ []string, defaultIndex int) int { return defaultIndex }
+
+func (defaultPrompter) MultiSelect(_ string, _ []string, selected []bool) []bool { return selected }
+
+// hunkHelp explains the answers of ReviewHunks, as in `git add -p`
+const hunkHelp = `y - apply this hunk
+n - skip this hunk
+a - apply this hunk and all later hunks
+d - skip this hunk and all later hunks
+q - quit; skip this hunk and all later hunks
+? - print help
+`
+
+// ReviewHunks shows each hunk of a diff to name and asks whether to apply
+// it. It returns one decision per hunk; once input ends every remaining
+// hunk is skipped.
+func ReviewHunks(prompt Prompter, out io.Writer, name string, hunks []utils.DiffHunk, color bool) []bool {
+	accepted := make([]bool, len(hunks))
+
+	for i := 0; i < len(hunks); i++ {
+		fmt.Fprint(out, FormatHunk(hunks[i], color))
+		answer := prompt.Input(fmt.Sprintf("(%d/%d) Apply this hunk to %s [y,n,a,d,q,?]", i+1, len(hunks), name), "n", nil)
+
+		switch strings.ToLower(answer) {
+		case "y", "yes":
+			accepted[i] = true
+		case "n", "no":
+		case "a":
+			for j := i; j < len(hunks); j++ {
+				accepted[j] = true
+			}
+			return accepted
+		case "d", "q":
+			return accepted
+		default:
+			fmt.Fprint(out, paint(hunkHelp, ColorYellow, color))
+			i-- // ask again
+		}
+	}
+	return accepted
+}
@@ -1,205 +1,659 @@
 package ui
 
 import (
-	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/gaoubak/Makegen/internal/config"
 	"github.com/gaoubak/Makegen/internal/detector"
+	"github.com/gaoubak/Makegen/internal/generator"
+	"github.com/gaoubak/Makegen/internal/storage"
 	"github.com/gaoubak/Makegen/internal/utils"
 )
 
 // Questionnaire manages the interactive question flow
 type Questionnaire struct {
-	logger    *utils.Logger
-	detection *detector.Result
-	reader    *bufio.Reader
-	config    *config.MakefileConfig
+	logger      *utils.Logger
+	detection   *detector.Result
+	prompt      Prompter
+	out         io.Writer
+	config      *config.MakefileConfig
+	defaultName string
 }
 
-// NewQuestionnaire creates a new questionnaire
+// NewQuestionnaire creates a new questionnaire asking on stdin and stdout
 func NewQuestionnaire(logger *utils.Logger, detection *detector.Result) *Questionnaire {
+	return NewQuestionnaireWithPrompter(logger, detection, NewPrompter(os.Stdin, os.Stdout), os.Stdout)
+}
+
+// NewQuestionnaireWithPrompter creates a questionnaire asking through
+// prompt and writing its headings to out. Sharing one prompter keeps a
+// single reader on the input, so scripted answers are never lost.
+func NewQuestionnaireWithPrompter(logger *utils.Logger, detection *detector.Result, prompt Prompter, out io.Writer) *Questionnaire {
 	return &Questionnaire{
-		logger:    logger,
-		detection: detection,
-		reader:    bufio.NewReader(os.Stdin),
-		config:    config.NewMakefileConfig(),
+		logger:      logger,
+		detection:   detection,
+		prompt:      prompt,
+		out:         out,
+		config:      config.NewMakefileConfig(),
+		defaultName: "myproject",
 	}
 }
 
-// Ask runs the interactive questionnaire
+// section is a group of questions that the review screen can ask again.
+// Asking a section replaces all of its answers.
+type section struct {
+	title   string
+	ask     func()
+	summary func() string
+	show    func() bool // nil when the section always applies
+}
+
+// sections lists the questions in the order they are asked
+func (q *Questionnaire) sections() []section {
+	c := q.config
+	return []section{
+		// Phase 1: Project Info
+		{title: "Project name", ask: q.askProjectName, summary: func() string { return c.ProjectName }},
+		{title: "Framework", ask: q.askFramework, summary: func() string {
+			if c.Framework == nil {
+				return "none"
+			}
+			return c.Framework.Name
+		}, show: func() bool { return len(q.detection.Frameworks) > 0 }},
+
+		// Phase 2: Docker
+		{title: "Docker", ask: q.askDocker, summary: func() string {
+			if !c.HasDocker {
+				return "no"
+			}
+			parts := []string{"yes"}
+			if c.DockerImage != "" {
+				parts = append(parts, "image "+c.DockerImage)
+			}
+			if c.DockerCompose {
+				parts = append(parts, "compose "+listSummary(c.DockerServices))
+			}
+			return strings.Join(parts, ", ")
+		}},
+
+		// Phase 3: Build & Test
+		{title: "Code generation", ask: q.askCodeGeneration, summary: func() string {
+			var names []string
+			for _, gen := range c.Generators {
+				names = append(names, gen.Name)
+			}
+			return listSummary(names)
+		}, show: func() bool { return len(q.detection.Generators) > 0 }},
+		{title: "Database", ask: q.askDatabase, summary: func() string { return valueSummary(c.MigrationTool) },
+			show: func() bool { return q.detection.MigrationTool != "" }},
+		{title: "Tests", ask: q.askTestSetup, summary: func() string {
+			var parts []string
+			if c.TestFramework != "" {
+				parts = append(parts, c.TestFramework)
+			}
+			for _, opt := range []struct {
+				on   bool
+				name string
+			}{{c.IntegrationTests, "integration"}, {c.EnableCoverage, "coverage"}, {c.EnableBench, "bench"}, {c.E2EFramework != "", c.E2EFramework}} {
+				if opt.on {
+					parts = append(parts, opt.name)
+				}
+			}
+			return listSummary(parts)
+		}},
+
+		// Phase 4: Quality
+		{title: "Linting", ask: q.askLinting, summary: func() string { return listSummary(c.LintTools) }},
+		{title: "Formatting", ask: q.askFormatting, summary: func() string { return listSummary(c.FormatTools) }},
+		{title: "Security", ask: q.askSecurity, summary: func() string { return yesNo(c.EnableSecurity) }},
+		{title: "Pinned tools", ask: q.askTools, summary: func() string { return yesNo(c.PinTools) },
+			show: q.hasPinnableTools},
+		{title: "Tool checks", ask: q.askGuards, summary: func() string { return yesNo(c.EnableGuards) }},
+
+		// Phase 5: Advanced
+		{title: "Release", ask: q.askRelease, summary: func() string {
+			if !c.EnableRelease {
+				return "no"
+			}
+			return strings.Join(c.Platforms, " ")
+		}, show: func() bool { return c.Language == "go" }},
+		{title: "CI", ask: q.askCICD, summary: func() string { return yesNo(c.EnableCI) }},
+		{title: "Deployment", ask: q.askDeployment, summary: func() string { return yesNo(c.EnableDeploy) }},
+		{title: "Custom targets", ask: q.askCustomTargets, summary: func() string { return listSummary(q.customTargetNames()) }},
+	}
+}
+
+// Ask runs the interactive questionnaire, then lets any answer be changed
+// on a review screen
 func (q *Questionnaire) Ask() (*config.MakefileConfig, error) {
-	// Phase 1: Project Info
-	q.askProjectName()
-	q.askFramework()
+	q.applyDetection()
 
-	// Phase 2: Docker
-	q.askDocker()
+	sections := q.sections()
+	for _, s := range sections {
+		if s.show == nil || s.show() {
+			s.ask()
+		}
+	}
+	q.review(sections)
 
-	// Phase 3: Build & Test
-	q.askBuildTargets()
-	q.askTestSetup()
+	return q.config, nil
+}
+
+// Defaults runs the questionnaire without prompting: every question takes
+// its default answer and the project is named projectName
+func (q *Questionnaire) Defaults(projectName string) (*config.MakefileConfig, error) {
+	q.prompt = defaultPrompter{}
+	q.out = io.Discard
+	q.defaultName = projectName
+	return q.Ask()
+}
 
-	// Phase 4: Quality
-	q.askLinting()
-	q.askFormatting()
+// Refresh takes the answers of an earlier run instead of asking, with the
+// detected toolchain copied over them again so that they follow the
+// project, and names the project projectName
+func (q *Questionnaire) Refresh(saved *config.MakefileConfig, projectName string) *config.MakefileConfig {
+	q.config = saved
+	q.defaultName = projectName
+	q.applyDetection()
+	return q.config
+}
 
-	// Phase 5: Advanced
-	q.askCICD()
-	q.askDeployment()
-	q.askCustomTargets()
+// review lists the answers until the user picks "Generate"; picking an
+// answer asks its questions again
+func (q *Questionnaire) review(sections []section) {
+	for {
+		var shown []section
+		options := []string{"✔ Generate Makefile"}
+		for _, s := range sections {
+			if s.show == nil || s.show() {
+				shown = append(shown, s)
+				options = append(options, fmt.Sprintf("%-16s %s", s.title+":", s.summary()))
+			}
+		}
 
-	return q.config, nil
+		fmt.Fprintln(q.out)
+		choice := q.prompt.Select("📋 Review: pick an answer to change", options, 0)
+		if choice == 0 {
+			return
+		}
+		shown[choice-1].ask()
+	}
+}
+
+// applyDetection copies the detected toolchain into the config
+func (q *Questionnaire) applyDetection() {
+	q.config.ProjectName = q.defaultName
+	q.config.Language = q.detection.Language
+	q.config.BuildTool = q.detection.BuildTool
+	q.config.UseWrapper = q.detection.HasWrapper
+	q.config.Modules = q.detection.Modules
+	q.config.BuildPresets = q.detection.BuildPresets
+	q.config.Entrypoint = q.detection.MainEntrypoint
+	q.config.ModulePath = q.detection.ModulePath
+	q.config.Binaries = q.detection.Binaries
+	q.config.BenchPackages = q.detection.BenchPackages
+	q.config.PackageManager = q.detection.PackageManager
+	q.config.GolangciLintV2 = q.detection.GolangciLintV2
+}
+
+// chooseAll offers detected items as checkboxes, all checked, and returns
+// the ones kept
+func (q *Questionnaire) chooseAll(message string, options []string) []string {
+	selected := make([]bool, len(options))
+	for i := range selected {
+		selected[i] = true
+	}
+
+	chosen := []string{}
+	for i, ok := range q.prompt.MultiSelect(message, options, selected) {
+		if ok {
+			chosen = append(chosen, options[i])
+		}
+	}
+	return chosen
+}
+
+func listSummary(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
+
+func valueSummary(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
 }
 
 // Helper prompts
 func (q *Questionnaire) askProjectName() {
-	fmt.Print("\n📝 Project name: ")
-	name, _ := q.reader.ReadString('\n')
-	name = strings.TrimSpace(name)
-	if name != "" {
-		q.config.ProjectName = name
-	} else {
-		q.config.ProjectName = "myproject"
-	}
-	q.logger.Info("✓ Project: %s", q.config.ProjectName)
+	fmt.Fprintln(q.out)
+	q.config.ProjectName = q.prompt.Input("📝 Project name", q.config.ProjectName, utils.ValidateProjectName)
+	fmt.Fprintf(q.out, "✓ Project: %s\n", q.config.ProjectName)
 }
 
 func (q *Questionnaire) askFramework() {
+	q.config.Framework = nil
 	if len(q.detection.Frameworks) == 0 {
 		return
 	}
 
-	fmt.Println("\n🎯 Detected Frameworks:")
-	for i, fw := range q.detection.Frameworks {
-		fmt.Printf("  %d. %s (%s)\n", i+1, fw.Name, fw.Type)
+	fmt.Fprintln(q.out)
+	var options []string
+	for _, fw := range q.detection.Frameworks {
+		options = append(options, fmt.Sprintf("%s (%s)", fw.Name, fw.Type))
 	}
+	options = append(options, "None")
 
-	if PromptYesNo("Use a detected framework?", true) {
-		// TODO: Implement framework selection
+	choice := q.prompt.Select("🎯 Framework", options, 0)
+	if choice == len(q.detection.Frameworks) {
+		return
 	}
+
+	fw := q.detection.Frameworks[choice]
+	q.config.Framework = &config.FrameworkConfig{
+		Name:     fw.Name,
+		Type:     fw.Type,
+		Commands: fw.Commands,
+		Port:     fw.Port,
+	}
+	fmt.Fprintf(q.out, "✓ Framework: %s\n", fw.Name)
 }
 
 func (q *Questionnaire) askDocker() {
+	image := q.config.DockerImage
+	q.config.HasDocker = false
+	q.config.DockerServices = nil
+	q.config.DockerImage = ""
+	q.config.DockerCompose = false
+
 	if !q.detection.DockerDetected {
-		if PromptYesNo("\n🐳 Add Docker support?", false) {
-			q.config.HasDocker = true
-		}
+		fmt.Fprintln(q.out)
+		q.config.HasDocker = q.prompt.Confirm("🐳 Add Docker support?", false)
 		return
 	}
 
-	fmt.Println("\n🐳 Docker detected!")
-	if len(q.detection.DockerServices) > 0 {
-		fmt.Printf("   Services: %v\n", q.detection.DockerServices)
+	fmt.Fprintln(q.out, "\n🐳 Docker detected!")
+	if !q.prompt.Confirm("Add Docker targets?", true) {
+		return
 	}
+	q.config.HasDocker = true
+	q.config.DockerImage = q.prompt.Input("Docker image name", image, Optional(validateImageName))
 
-	if PromptYesNo("Add Docker targets?", true) {
-		q.config.HasDocker = true
+	if q.prompt.Confirm("Add docker-compose targets?", true) {
+		q.config.DockerCompose = true
 		q.config.DockerServices = q.detection.DockerServices
-
-		fmt.Print("Docker image name: ")
-		name, _ := q.reader.ReadString('\n')
-		name = strings.TrimSpace(name)
-		if name != "" {
-			q.config.DockerImage = name
+		if len(q.detection.DockerServices) > 1 {
+			q.config.DockerServices = q.chooseAll("Compose services", q.detection.DockerServices)
 		}
+	}
+}
+
+func (q *Questionnaire) askCodeGeneration() {
+	q.config.Generators = nil
+	if len(q.detection.Generators) == 0 {
+		return
+	}
+
+	fmt.Fprintln(q.out, "\n🔨 Code Generation")
+
+	var names []string
+	for _, gen := range q.detection.Generators {
+		names = append(names, gen.Name)
+	}
 
-		if PromptYesNo("Add docker-compose targets?", true) {
-			q.config.DockerCompose = true
+	chosen := q.chooseAll("Code generators for 'generate'", names)
+	for _, gen := range q.detection.Generators {
+		if slices.Contains(chosen, gen.Name) {
+			q.config.Generators = append(q.config.Generators, config.CodeGenerator{Name: gen.Name, Config: gen.Config})
 		}
 	}
 }
 
-func (q *Questionnaire) askBuildTargets() {
-	fmt.Println("\n🔨 Build Configuration")
-
-	// TODO: Language-specific build targets
-	if PromptYesNo("Add 'build' target?", true) {
-		// Add build target
+func (q *Questionnaire) askDatabase() {
+	q.config.MigrationTool = ""
+	q.config.MigrationDir = ""
+	q.config.DatabaseService = ""
+	if q.detection.MigrationTool == "" {
+		return
 	}
-	if PromptYesNo("Add 'clean' target?", true) {
-		// Add clean target
+
+	fmt.Fprintf(q.out, "\n🗄️  Migrations detected: %s\n", q.detection.MigrationTool)
+	if q.detection.DatabaseService != "" {
+		fmt.Fprintf(q.out, "   Database service: %s\n", q.detection.DatabaseService)
 	}
-	if PromptYesNo("Add 'run' target?", true) {
-		// Add run target
+
+	if q.prompt.Confirm("Add database migration targets?", true) {
+		q.config.MigrationTool = q.detection.MigrationTool
+		q.config.MigrationDir = q.detection.MigrationDir
+		q.config.DatabaseService = q.detection.DatabaseService
 	}
 }
 
 func (q *Questionnaire) askTestSetup() {
-	fmt.Println("\n🧪 Testing Configuration")
+	q.config.TestFramework = ""
+	q.config.IntegrationTests = false
+	q.config.EnableCoverage = false
+	q.config.EnableBench = false
+	q.config.E2EFramework = ""
+	fmt.Fprintln(q.out, "\n🧪 Testing Configuration")
 
 	if !q.detection.TestDirFound {
-		if !PromptYesNo("No test directory found. Add test target anyway?", false) {
+		if !q.prompt.Confirm("No test directory found. Add test target anyway?", false) {
 			return
 		}
 	}
 
-	if PromptYesNo("Add 'test' target?", true) {
-		// TODO: Test framework selection
-		if PromptYesNo("Add coverage target?", true) {
-			// Add coverage target
+	if q.detection.TestFramework != "" {
+		fmt.Fprintf(q.out, "   Test framework: %s\n", q.detection.TestFramework)
+	}
+
+	if q.prompt.Confirm("Add 'test' target?", true) {
+		q.config.TestFramework = q.detection.TestFramework
+		if q.detection.IntegrationTests && q.prompt.Confirm("Add 'test-unit' and 'test-integration' targets?", true) {
+			q.config.IntegrationTests = true
+		}
+		if q.config.TestFramework != "" && q.prompt.Confirm("Add coverage target?", true) {
+			q.config.EnableCoverage = true
+		}
+		if q.detection.HasBenchmarks && q.prompt.Confirm("Add benchmark targets?", true) {
+			q.config.EnableBench = true
+		}
+	}
+
+	if q.detection.E2EFramework != "" {
+		fmt.Fprintf(q.out, "   End-to-end tests: %s\n", q.detection.E2EFramework)
+		if q.prompt.Confirm("Add 'test-e2e' target?", true) {
+			q.config.E2EFramework = q.detection.E2EFramework
 		}
 	}
 }
 
 func (q *Questionnaire) askLinting() {
-	fmt.Println("\n🔍 Linting Configuration")
+	q.config.LintTools = []string{}
+	fmt.Fprintln(q.out, "\n🔍 Linting Configuration")
 
-	if PromptYesNo("Add 'lint' target?", true) {
-		// TODO: Linter selection
+	if len(q.detection.LintTools) == 0 {
+		fmt.Fprintln(q.out, "   No linter configuration found")
+		return
 	}
+
+	q.config.LintTools = q.chooseAll("Linters for 'lint' and 'lint-fix'", q.detection.LintTools)
 }
 
 func (q *Questionnaire) askFormatting() {
-	fmt.Println("\n✨ Code Formatting")
+	q.config.FormatTools = []string{}
+	fmt.Fprintln(q.out, "\n✨ Code Formatting")
 
-	if PromptYesNo("Add 'format' target?", true) {
-		// TODO: Formatter selection
+	if len(q.detection.FormatTools) == 0 {
+		fmt.Fprintln(q.out, "   No formatter configuration found")
+		return
 	}
+
+	q.config.FormatTools = q.chooseAll("Formatters for 'format' and 'format-check'", q.detection.FormatTools)
 }
 
-func (q *Questionnaire) askCICD() {
-	fmt.Println("\n🔄 CI/CD Configuration")
+func (q *Questionnaire) askSecurity() {
+	fmt.Fprintln(q.out, "\n🔒 Security")
 
-	if PromptYesNo("Add GitHub Actions CI target?", false) {
-		q.config.EnableCI = true
-		// TODO: CI/CD configuration
+	q.config.EnableSecurity = q.prompt.Confirm("Add audit, sbom and secrets-scan targets?", false)
+}
+
+func (q *Questionnaire) askGuards() {
+	fmt.Fprintln(q.out, "\n🩺 Tool Checks")
+	fmt.Fprintln(q.out, "   A 'doctor' target reports missing or outdated tools")
+
+	q.config.EnableGuards = q.prompt.Confirm("Also check required tools before each target runs?", false)
+}
+
+// hasPinnableTools reports whether any lint, format or security tool is
+// used, so pinning them makes sense
+func (q *Questionnaire) hasPinnableTools() bool {
+	return len(q.config.LintTools) > 0 || len(q.config.FormatTools) > 0 || q.config.EnableSecurity
+}
+
+func (q *Questionnaire) askTools() {
+	q.config.PinTools = false
+	if !q.hasPinnableTools() {
+		return
 	}
+
+	q.config.PinTools = q.prompt.Confirm("Pin tool versions and install them into .bin/ with 'make tools'?", false)
 }
 
-func (q *Questionnaire) askDeployment() {
-	fmt.Println("\n🚀 Deployment Configuration")
+func (q *Questionnaire) askRelease() {
+	q.config.EnableRelease = false
+	if q.config.Language != "go" {
+		return
+	}
+
+	fmt.Fprintln(q.out, "\n📦 Release Configuration")
 
-	if PromptYesNo("Add deployment targets?", false) {
-		q.config.EnableDeploy = true
-		// TODO: Deployment target selection
+	if !q.prompt.Confirm("Add cross-compilation release target?", false) {
+		return
+	}
+	q.config.EnableRelease = true
+
+	platforms := q.prompt.Input("Platforms", strings.Join(q.config.Platforms, " "), EachField(validatePlatform))
+	if fields := strings.Fields(platforms); len(fields) > 0 {
+		q.config.Platforms = fields
 	}
 }
 
+func (q *Questionnaire) askCICD() {
+	fmt.Fprintln(q.out, "\n🔄 CI/CD Configuration")
+
+	q.config.EnableCI = q.prompt.Confirm("Add GitHub Actions CI target?", false)
+	// TODO: CI/CD configuration
+}
+
+func (q *Questionnaire) askDeployment() {
+	fmt.Fprintln(q.out, "\n🚀 Deployment Configuration")
+
+	q.config.EnableDeploy = q.prompt.Confirm("Add deployment targets?", false)
+	// TODO: Deployment target selection
+}
+
+// askCustomTargets keeps the targets already added, so going back to it
+// from the review screen edits them rather than starting over
 func (q *Questionnaire) askCustomTargets() {
-	fmt.Println("\n✨ Custom Targets")
+	fmt.Fprintln(q.out, "\n✨ Custom Targets")
 
-	for PromptYesNo("Add custom target?", false) {
-		// TODO: Custom target input
+	if len(q.config.CustomTargets) == 0 {
+		if !q.prompt.Confirm("Add custom target?", false) {
+			return
+		}
+		q.editCustomTarget(config.NewTarget(""))
+	}
+
+	for {
+		names := q.customTargetNames()
+		options := []string{"Done", "Add a target"}
+		for _, name := range names {
+			options = append(options, "Edit "+name, "Remove "+name)
+		}
+
+		fmt.Fprintln(q.out)
+		choice := q.prompt.Select("Custom targets", options, 0)
+		switch {
+		case choice == 0:
+			return
+		case choice == 1:
+			q.editCustomTarget(config.NewTarget(""))
+		case choice%2 == 0:
+			target := q.config.CustomTargets[names[(choice-2)/2]]
+			q.editCustomTarget(&target)
+		default:
+			delete(q.config.CustomTargets, names[(choice-2)/2])
+			q.renameDependency(names[(choice-2)/2], "")
+		}
 	}
 }
 
-// PromptYesNo asks a yes/no question
-func PromptYesNo(message string, defaultYes bool) bool {
-	suffix := "[Y/n]"
-	if !defaultYes {
-		suffix = "[y/N]"
+// editCustomTarget asks for each field of target, starting from its
+// current values, previews the rule and stores it unless the user drops it
+func (q *Questionnaire) editCustomTarget(target *config.Target) {
+	previous := target.Name
+	generated, others := q.existingTargets(previous)
+
+	target.Name = q.prompt.Input("Target name", target.Name, func(name string) error {
+		if err := utils.ValidateTargetName(name); err != nil {
+			return err
+		}
+		if slices.Contains(generated, name) {
+			return fmt.Errorf("%q is already a generated target", name)
+		}
+		if slices.Contains(others, name) {
+			return fmt.Errorf("%q is already a custom target", name)
+		}
+		return nil
+	})
+	if target.Name == "" {
+		return
 	}
-	fmt.Printf("%s %s: ", message, suffix)
+	target.Description = q.prompt.Input("Description (shown above the rule)", target.Description, nil)
+
+	// Dependencies are picked from the generated and other custom targets
+	candidates := append(append([]string{}, generated...), others...)
+	if len(candidates) > 0 {
+		selected := make([]bool, len(candidates))
+		for i, candidate := range candidates {
+			selected[i] = slices.Contains(target.Dependencies, candidate)
+		}
+		for {
+			deps := []string{}
+			for i, ok := range q.prompt.MultiSelect("Dependencies", candidates, selected) {
+				if ok {
+					deps = append(deps, candidates[i])
+				}
+			}
+			cycle := slices.IndexFunc(deps, func(dep string) bool {
+				return q.dependsOn(dep, previous) || q.dependsOn(dep, target.Name)
+			})
+			if cycle < 0 {
+				target.Dependencies = deps
+				break
+			}
+			fmt.Fprintf(q.out, "  ✗ %s already depends on %s, so depending on it would make a cycle\n", deps[cycle], target.Name)
+		}
+	}
+	q.previewTarget(target)
+
+	if len(target.Commands) == 0 || !q.prompt.Confirm(fmt.Sprintf("Keep the %d current command(s)?", len(target.Commands)), true) {
+		fmt.Fprintln(q.out, "   Commands, one per line; an empty line ends them")
+		target.Commands = []string{}
+		for {
+			command := q.prompt.Input(fmt.Sprintf("   %d", len(target.Commands)+1), "", nil)
+			if command == "" {
+				break
+			}
+			target.Commands = append(target.Commands, command)
+		}
+	}
+	q.previewTarget(target)
+
+	target.Phony = q.prompt.Confirm("Phony target (does not create a file of that name)?", target.Phony)
+	target.Guard = q.prompt.Input("Variable that must be set, e.g. ENV (empty for none)", target.Guard, Optional(utils.ValidateVariableName))
 
-	reader := bufio.NewReader(os.Stdin)
-	response, _ := reader.ReadString('\n')
-	response = strings.ToLower(strings.TrimSpace(response))
+	q.previewTarget(target)
+	if !q.prompt.Confirm("Keep this target?", true) {
+		return
+	}
+	delete(q.config.CustomTargets, previous)
+	q.config.CustomTargets[target.Name] = *target
+	if previous != "" && previous != target.Name {
+		q.renameDependency(previous, target.Name)
+	}
+}
+
+// previewTarget prints the rule as it would be written so far
+func (q *Questionnaire) previewTarget(target *config.Target) {
+	fmt.Fprintf(q.out, "\n%s", generator.FormatTarget(*target))
+}
+
+// dependsOn reports whether the custom target from reaches the target
+// named to through its dependencies
+func (q *Questionnaire) dependsOn(from, to string) bool {
+	if to == "" {
+		return false
+	}
+	seen := map[string]bool{}
+	pending := []string{from}
+	for len(pending) > 0 {
+		name := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if name == to {
+			return true
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		pending = append(pending, q.config.CustomTargets[name].Dependencies...)
+	}
+	return false
+}
+
+// renameDependency follows a renamed custom target in the dependencies of
+// the others; an empty name drops it
+func (q *Questionnaire) renameDependency(from, to string) {
+	for name, target := range q.config.CustomTargets {
+		if !slices.Contains(target.Dependencies, from) {
+			continue
+		}
+		deps := []string{}
+		for _, dep := range target.Dependencies {
+			if dep != from {
+				deps = append(deps, dep)
+			} else if to != "" {
+				deps = append(deps, to)
+			}
+		}
+		target.Dependencies = deps
+		q.config.CustomTargets[name] = target
+	}
+}
+
+// existingTargets returns the targets the Makefile generates from the
+// other answers and the custom targets other than exclude
+func (q *Questionnaire) existingTargets(exclude string) (generated, others []string) {
+	cfg := *q.config
+	cfg.CustomTargets = nil
+	if makefile, err := generator.NewBuilder(q.logger).Build(&cfg); err == nil {
+		for _, name := range storage.ParseMakefile(makefile).Targets() {
+			if !slices.Contains(generated, name) {
+				generated = append(generated, name)
+			}
+		}
+	}
+
+	for _, name := range q.customTargetNames() {
+		if name != exclude {
+			others = append(others, name)
+		}
+	}
+	return generated, others
+}
 
-	if response == "" {
-		return defaultYes
+func (q *Questionnaire) customTargetNames() []string {
+	names := make([]string, 0, len(q.config.CustomTargets))
+	for name := range q.config.CustomTargets {
+		names = append(names, name)
 	}
-	return response == "y" || response == "yes"
+	sort.Strings(names)
+	return names
 }
@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// rawMode switches a terminal to unbuffered, unechoed input so widgets see
+// every key, and returns a function restoring the previous settings. It
+// relies on stty, so it fails where stty is missing and callers fall back
+// to line prompts.
+func rawMode(f *os.File) (func(), error) {
+	state, err := stty(f, "-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty(f, "-icanon", "-echo", "-isig", "min", "1", "time", "0"); err != nil {
+		return nil, err
+	}
+	return func() { stty(f, state) }, nil
+}
+
+// stty runs stty on the terminal f and returns its trimmed output
+func stty(f *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = f
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// Terminal control sequences
+const (
+	hideCursor = "\033[?25l"
+	showCursor = "\033[?25h"
+	clearDown  = "\033[J"
+)
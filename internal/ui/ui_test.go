@@ -1,7 +1,242 @@
 package ui
 
-import "testing"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
 
-func TestUI(t *testing.T) {
-	t.Skip("Implementation pending")
+	"github.com/gaoubak/Makegen/internal/config"
+	"github.com/gaoubak/Makegen/internal/detector"
+	"github.com/gaoubak/Makegen/internal/utils"
+)
+
+func TestFormatDiff(t *testing.T) {
+	hunks := utils.Diff("a\nb\n", "a\nc\n", 3)
+
+	want := "--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n"
+	if got := FormatDiff("old", "new", hunks, false); got != want {
+		t.Errorf("FormatDiff() =\n%s\nwant\n%s", got, want)
+	}
+
+	colored := FormatDiff("old", "new", hunks, true)
+	for _, want := range []string{ColorRed + "-b" + ColorReset, ColorGreen + "+c" + ColorReset, ColorCyan + "@@"} {
+		if !strings.Contains(colored, want) {
+			t.Errorf("FormatDiff() colored output missing %q", want)
+		}
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	if ColorEnabled(&bytes.Buffer{}) {
+		t.Error("ColorEnabled() should be false for a non-terminal writer")
+	}
+}
+
+func TestReviewHunks(t *testing.T) {
+	hunks := utils.Diff("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n", "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n", 1)
+	if len(hunks) != 2 {
+		t.Fatalf("expected 2 hunks, got %d", len(hunks))
+	}
+
+	tests := []struct {
+		input string
+		want  []bool
+	}{
+		{"y\nn\n", []bool{true, false}},
+		{"n\ny\n", []bool{false, true}},
+		{"a\n", []bool{true, true}},
+		{"y\nq\n", []bool{true, false}},
+		{"?\nd\n", []bool{false, false}},
+		{"y\n", []bool{true, false}}, // input ends
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if got := ReviewHunks(NewLinePrompter(strings.NewReader(tt.input), &out), &out, "Makefile", hunks, false); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ReviewHunks(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	var out bytes.Buffer
+	ReviewHunks(NewLinePrompter(strings.NewReader("?\nn\nn\n"), &out), &out, "Makefile", hunks, false)
+	if !strings.Contains(out.String(), "a - apply this hunk and all later hunks") ||
+		!strings.Contains(out.String(), "(2/2) Apply this hunk to Makefile [y,n,a,d,q,?] [n]: ") {
+		t.Errorf("ReviewHunks() output =\n%s", out.String())
+	}
+}
+
+func TestLinePrompter(t *testing.T) {
+	var out bytes.Buffer
+	p := NewLinePrompter(strings.NewReader("y\n\nbad name\ngood\n7\n2\n1,3\n\n"), &out)
+
+	if !p.Confirm("Docker?", false) {
+		t.Error("Confirm() = false, want true for y")
+	}
+	if !p.Confirm("Tests?", true) {
+		t.Error("Confirm() = false, want the default for an empty answer")
+	}
+
+	noSpaces := func(s string) error {
+		if strings.Contains(s, " ") {
+			return errors.New("no spaces")
+		}
+		return nil
+	}
+	if got := p.Input("Name", "demo", noSpaces); got != "good" {
+		t.Errorf("Input() = %q, want the first valid answer", got)
+	}
+	if !strings.Contains(out.String(), "  ✗ no spaces\n") {
+		t.Errorf("Input() did not report the validation error:\n%s", out.String())
+	}
+
+	if got := p.Select("Framework", []string{"gin", "echo", "none"}, 0); got != 1 {
+		t.Errorf("Select() = %d, want 1 after an out of range answer", got)
+	}
+	if got := p.MultiSelect("Tools", []string{"a", "b", "c"}, []bool{true, true, false}); !reflect.DeepEqual(got, []bool{true, false, true}) {
+		t.Errorf("MultiSelect() = %v", got)
+	}
+	if got := p.MultiSelect("Tools", []string{"a", "b"}, []bool{false, true}); !reflect.DeepEqual(got, []bool{false, true}) {
+		t.Errorf("MultiSelect() = %v, want the selection kept on Enter", got)
+	}
+
+	// Input has ended: defaults
+	if got := p.Input("Image", "app", nil); got != "app" {
+		t.Errorf("Input() at end of input = %q, want default", got)
+	}
+	if got := p.Select("Framework", []string{"gin", "echo"}, 1); got != 1 {
+		t.Errorf("Select() at end of input = %d, want default", got)
+	}
+}
+
+var update = flag.Bool("update", false, "rewrite fixtures/expected_prompts.txt")
+
+// TestQuestionnaireReplay answers the questionnaire from
+// fixtures/sample_responses.json and compares everything it prints with
+// fixtures/expected_prompts.txt. Run with -update after changing prompts.
+func TestQuestionnaireReplay(t *testing.T) {
+	data, err := os.ReadFile("fixtures/sample_responses.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fixture struct {
+		ProjectName string          `json:"project_name"`
+		Detection   detector.Result `json:"detection"`
+		Responses   []string        `json:"responses"`
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	input := strings.Join(fixture.Responses, "\n") + "\n"
+	prompt := NewLinePrompter(strings.NewReader(input), &out)
+	q := NewQuestionnaireWithPrompter(utils.NewLogger(false), &fixture.Detection, prompt, &out)
+	q.defaultName = fixture.ProjectName
+
+	cfg, err := q.Ask()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *update {
+		if err := os.WriteFile("fixtures/expected_prompts.txt", out.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile("fixtures/expected_prompts.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != string(want) {
+		t.Errorf("prompts differ from fixtures/expected_prompts.txt:\n%s", FormatDiff("expected", "got", utils.Diff(string(want), out.String(), 3), false))
+	}
+
+	if cfg.ProjectName != "shop-api" || cfg.Framework == nil || cfg.Framework.Name != "gin" {
+		t.Errorf("project = %q, framework = %v", cfg.ProjectName, cfg.Framework)
+	}
+	if !reflect.DeepEqual(cfg.DockerServices, []string{"app", "db"}) {
+		t.Errorf("DockerServices = %v, want [app db]", cfg.DockerServices)
+	}
+	if !cfg.EnableSecurity || !cfg.EnableGuards || cfg.PinTools {
+		t.Errorf("security = %v, guards = %v, pinned = %v", cfg.EnableSecurity, cfg.EnableGuards, cfg.PinTools)
+	}
+	if !reflect.DeepEqual(cfg.FormatTools, []string{"goimports"}) {
+		t.Errorf("FormatTools = %v, want [goimports]", cfg.FormatTools)
+	}
+}
+
+func TestCustomTargetWizard(t *testing.T) {
+	detection := &detector.Result{Language: "go", TestDirFound: true, TestFramework: "go test"}
+	probe := NewQuestionnaireWithPrompter(utils.NewLogger(false), detection, defaultPrompter{}, io.Discard)
+	probe.applyDetection()
+	generated, _ := probe.existingTargets("")
+	customDep := strconv.Itoa(len(generated) + 1) // the other custom target follows the generated ones
+
+	input := strings.Join([]string{
+		"y", "seed db", "seed", "Load sample data", "", "./bin/seed", "", "", "1ENV", "ENV", "",
+		"2", "seed", "load", "", customDep, "echo loaded", "", "", "", "",
+		// load depends on seed, so seed cannot depend on load
+		"5", "seed-db", "", customDep, "", "", "n", "", "",
+		"1",
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	q := NewQuestionnaireWithPrompter(utils.NewLogger(false), detection, NewLinePrompter(strings.NewReader(input), &out), &out)
+	q.applyDetection()
+	q.askCustomTargets()
+
+	if got := q.customTargetNames(); !reflect.DeepEqual(got, []string{"load", "seed-db"}) {
+		t.Fatalf("custom targets = %v\n%s", got, out.String())
+	}
+	want := config.Target{Name: "seed-db", Description: "Load sample data", Dependencies: []string{}, Commands: []string{"./bin/seed"}, Guard: "ENV"}
+	if got := q.config.CustomTargets["seed-db"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("seed-db = %+v, want %+v", got, want)
+	}
+	// Renaming seed follows it into the dependencies of load
+	if got := q.config.CustomTargets["load"].Dependencies; !reflect.DeepEqual(got, []string{"seed-db"}) {
+		t.Errorf("load dependencies = %v, want [seed-db]", got)
+	}
+
+	for _, want := range []string{
+		"✗ target names must not contain spaces",
+		"✗ use letters, digits and underscores",
+		`✗ "seed" is already a custom target`,
+		"# Load sample data\nseed:\n\t@test -n \"$(ENV)\"",
+		// previews after the dependencies and after the commands
+		"# Load sample data\nseed:\n.PHONY: seed\n   Commands",
+		"# Load sample data\nseed:\n\t./bin/seed\n",
+		"✗ load already depends on seed-db, so depending on it would make a cycle",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q\n%s", want, out.String())
+		}
+	}
+}
+
+func TestPromptValidators(t *testing.T) {
+	image := Optional(validateImageName)
+	if image("") != nil || image("acme/app") != nil || image("acme/app:1.0") == nil || image("Acme") == nil {
+		t.Error("Optional(validateImageName) accepts the wrong names")
+	}
+	platforms := EachField(validatePlatform)
+	if platforms("linux/amd64 darwin/arm64") != nil || platforms("linux/amd64 linux") == nil || platforms("linux/amd64/v2") == nil {
+		t.Error("EachField(validatePlatform) accepts the wrong platforms")
+	}
+
+	// Prompts re-ask until the answer is valid
+	var out bytes.Buffer
+	p := NewLinePrompter(strings.NewReader("my app\n$(x)\nmy-app\n"), &out)
+	if got := p.Input("Project name", "", utils.ValidateProjectName); got != "my-app" {
+		t.Errorf("Input() = %q, want my-app", got)
+	}
+	if strings.Count(out.String(), "✗") != 2 {
+		t.Errorf("expected two errors:\n%s", out.String())
+	}
 }
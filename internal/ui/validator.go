@@ -1,3 +1,57 @@
 package ui
 
-// TODO: Implement input validation
+import (
+	"errors"
+	"strings"
+
+	"github.com/gaoubak/Makegen/internal/utils"
+)
+
+// Validator checks an answer to Input; the prompt shows the error and
+// asks again
+type Validator func(string) error
+
+// Optional accepts an empty answer and validates any other
+func Optional(validate Validator) Validator {
+	return func(value string) error {
+		if value == "" {
+			return nil
+		}
+		return validate(value)
+	}
+}
+
+// EachField validates every space-separated word of an answer
+func EachField(validate Validator) Validator {
+	return func(value string) error {
+		for _, field := range strings.Fields(value) {
+			if err := validate(field); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// validateImageName accepts an image name without a tag or digest, since
+// the Makefile tags the images it builds
+func validateImageName(value string) error {
+	image, err := utils.ParseDockerImage(value)
+	if err != nil {
+		return err
+	}
+	if image.Tag != "" || image.Digest != "" {
+		return errors.New("leave out the tag: the Makefile tags the image itself")
+	}
+	return nil
+}
+
+// validatePlatform accepts a GOOS/GOARCH pair such as linux/amd64
+func validatePlatform(value string) error {
+	goos, goarch, ok := strings.Cut(value, "/")
+	if !ok || goos == "" || goarch == "" || strings.Contains(goarch, "/") ||
+		utils.ValidateVariableName(goos) != nil || utils.ValidateVariableName(goarch) != nil {
+		return errors.New("platforms are GOOS/GOARCH pairs, e.g. linux/amd64")
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Keys read by the widgets
+const (
+	keyUp = iota + 1
+	keyDown
+	keyEnter
+	keySpace
+	keyInterrupt
+	keyOther
+)
+
+// ttyPrompter draws interactive widgets: arrow-key lists, checkboxes and
+// single-key confirmations. Text input uses the line prompt.
+type ttyPrompter struct {
+	in     *os.File
+	reader *bufio.Reader
+	out    io.Writer
+	lines  *LinePrompter
+}
+
+func newTTYPrompter(in *os.File, out io.Writer) *ttyPrompter {
+	reader := bufio.NewReader(in)
+	return &ttyPrompter{
+		in:     in,
+		reader: reader,
+		out:    out,
+		lines:  &LinePrompter{reader: reader, out: out, color: ColorEnabled(out)},
+	}
+}
+
+// Confirm reads a single y or n key; Enter takes the default
+func (p *ttyPrompter) Confirm(message string, defaultYes bool) bool {
+	suffix := "(Y/n)"
+	if !defaultYes {
+		suffix = "(y/N)"
+	}
+
+	answer := defaultYes
+	p.run(func(key int, r byte) bool {
+		switch {
+		case key == keyEnter:
+			return true
+		case r == 'y' || r == 'Y':
+			answer = true
+			return true
+		case r == 'n' || r == 'N':
+			answer = false
+			return true
+		}
+		return false
+	}, func() []string {
+		return []string{fmt.Sprintf("%s %s %s", p.paint("?", ColorGreen), message, p.paint(suffix, ColorBlue))}
+	})
+
+	p.done(message, map[bool]string{true: "yes", false: "no"}[answer])
+	return answer
+}
+
+// Input reads a line with the line prompt, which validates it
+func (p *ttyPrompter) Input(message, defaultValue string, validate func(string) error) string {
+	return p.lines.Input(p.paint("?", ColorGreen)+" "+message, defaultValue, validate)
+}
+
+// Select moves a cursor with the arrow keys (or j/k) and picks with Enter
+func (p *ttyPrompter) Select(message string, options []string, defaultIndex int) int {
+	cursor := defaultIndex
+	p.run(func(key int, _ byte) bool {
+		switch key {
+		case keyUp:
+			cursor = (cursor + len(options) - 1) % len(options)
+		case keyDown:
+			cursor = (cursor + 1) % len(options)
+		case keyEnter:
+			return true
+		}
+		return false
+	}, func() []string {
+		lines := []string{fmt.Sprintf("%s %s %s", p.paint("?", ColorGreen), message, p.paint("(↑/↓ move, enter select)", ColorBlue))}
+		for i, option := range options {
+			if i == cursor {
+				lines = append(lines, p.paint("❯ "+option, ColorCyan))
+			} else {
+				lines = append(lines, "  "+option)
+			}
+		}
+		return lines
+	})
+
+	p.done(message, options[cursor])
+	return cursor
+}
+
+// MultiSelect toggles options with space; a toggles all, Enter confirms
+func (p *ttyPrompter) MultiSelect(message string, options []string, selected []bool) []bool {
+	chosen := append([]bool(nil), selected...)
+	cursor := 0
+	p.run(func(key int, r byte) bool {
+		switch {
+		case key == keyUp:
+			cursor = (cursor + len(options) - 1) % len(options)
+		case key == keyDown:
+			cursor = (cursor + 1) % len(options)
+		case key == keySpace:
+			chosen[cursor] = !chosen[cursor]
+		case r == 'a':
+			all := true
+			for _, c := range chosen {
+				all = all && c
+			}
+			for i := range chosen {
+				chosen[i] = !all
+			}
+		case key == keyEnter:
+			return true
+		}
+		return false
+	}, func() []string {
+		lines := []string{fmt.Sprintf("%s %s %s", p.paint("?", ColorGreen), message, p.paint("(↑/↓ move, space toggle, a all, enter confirm)", ColorBlue))}
+		for i, option := range options {
+			box := "◯"
+			if chosen[i] {
+				box = p.paint("◉", ColorGreen)
+			}
+			line := fmt.Sprintf("  %s %s", box, option)
+			if i == cursor {
+				line = fmt.Sprintf("%s %s %s", p.paint("❯", ColorCyan), box, option)
+			}
+			lines = append(lines, line)
+		}
+		return lines
+	})
+
+	var names []string
+	for i, option := range options {
+		if chosen[i] {
+			names = append(names, option)
+		}
+	}
+	p.done(message, strings.Join(names, ", "))
+	return chosen
+}
+
+// run draws a widget and feeds it keys in raw mode until handle returns
+// true. Ctrl-C restores the terminal and exits like an interrupted shell
+// command. Without raw mode or input the widget keeps its defaults.
+func (p *ttyPrompter) run(handle func(key int, r byte) bool, render func() []string) {
+	restore, err := rawMode(p.in)
+	if err != nil {
+		return
+	}
+	fmt.Fprint(p.out, hideCursor)
+	defer func() {
+		fmt.Fprint(p.out, showCursor)
+		restore()
+	}()
+
+	drawn := 0
+	for {
+		if drawn > 0 {
+			fmt.Fprintf(p.out, "\033[%dA\r%s", drawn, clearDown)
+		}
+		lines := render()
+		fmt.Fprintf(p.out, "%s\n", strings.Join(lines, "\n"))
+		drawn = len(lines)
+
+		key, r, err := p.readKey()
+		if err != nil {
+			break
+		}
+		if key == keyInterrupt {
+			fmt.Fprint(p.out, showCursor)
+			restore()
+			os.Exit(130)
+		}
+		if handle(key, r) {
+			break
+		}
+	}
+	fmt.Fprintf(p.out, "\033[%dA\r%s", drawn, clearDown)
+}
+
+// readKey decodes one key press, including arrow escape sequences
+func (p *ttyPrompter) readKey() (int, byte, error) {
+	b, err := p.reader.ReadByte()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	switch b {
+	case 3:
+		return keyInterrupt, b, nil
+	case '\r', '\n':
+		return keyEnter, b, nil
+	case ' ':
+		return keySpace, b, nil
+	case 'k':
+		return keyUp, b, nil
+	case 'j':
+		return keyDown, b, nil
+	case 27:
+		// ESC [ A and ESC O A are both sent for arrows
+		if next, err := p.reader.ReadByte(); err != nil || (next != '[' && next != 'O') {
+			return keyOther, b, nil
+		}
+		switch arrow, _ := p.reader.ReadByte(); arrow {
+		case 'A':
+			return keyUp, b, nil
+		case 'B':
+			return keyDown, b, nil
+		}
+	}
+	return keyOther, b, nil
+}
+
+// done replaces a widget with a one-line summary of the answer
+func (p *ttyPrompter) done(message, answer string) {
+	fmt.Fprintf(p.out, "%s %s %s\n", p.paint("✔", ColorGreen), message, p.paint(answer, ColorCyan))
+}
+
+func (p *ttyPrompter) paint(text, color string) string {
+	return paint(text, color, p.lines.color)
+}
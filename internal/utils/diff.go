@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffLine is one line of a diff. Kind is ' ' for context, '-' for a
+// removed line and '+' for an added one.
+type DiffLine struct {
+	Kind byte
+	Text string
+}
+
+// DiffHunk is a run of changes with the unchanged lines around them.
+// Starts are 1-based line numbers, as in unified diffs.
+type DiffHunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	Lines              []DiffLine
+}
+
+// Diff compares two texts line by line and groups the changes into hunks
+// with up to context unchanged lines around them. Equal texts have no hunks.
+func Diff(oldText, newText string, context int) []DiffHunk {
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	// Line numbers in both files before each op
+	oldAt := make([]int, len(ops)+1)
+	newAt := make([]int, len(ops)+1)
+	var changes []int
+	for i, op := range ops {
+		oldAt[i+1], newAt[i+1] = oldAt[i], newAt[i]
+		if op.Kind != '+' {
+			oldAt[i+1]++
+		}
+		if op.Kind != '-' {
+			newAt[i+1]++
+		}
+		if op.Kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+
+	var hunks []DiffHunk
+	for c := 0; c < len(changes); {
+		// Changes closer than two contexts apart share a hunk
+		first, end := changes[c], changes[c]
+		for c++; c < len(changes) && changes[c]-end <= 2*context; c++ {
+			end = changes[c]
+		}
+
+		from := max(first-context, 0)
+		to := min(end+context+1, len(ops))
+
+		hunk := DiffHunk{OldStart: oldAt[from] + 1, NewStart: newAt[from] + 1, Lines: ops[from:to]}
+		for _, line := range hunk.Lines {
+			if line.Kind != '+' {
+				hunk.OldLines++
+			}
+			if line.Kind != '-' {
+				hunk.NewLines++
+			}
+		}
+		// An empty side is numbered after the line it follows
+		if hunk.OldLines == 0 {
+			hunk.OldStart--
+		}
+		if hunk.NewLines == 0 {
+			hunk.NewStart--
+		}
+		hunks = append(hunks, hunk)
+	}
+	return hunks
+}
+
+// ApplyHunks applies the accepted hunks of a diff computed against
+// oldText and leaves the old lines of the others
+func ApplyHunks(oldText string, hunks []DiffHunk, accepted []bool) string {
+	oldLines := splitLines(oldText)
+	var result []string
+	next := 0 // first old line not copied yet
+
+	for i, hunk := range hunks {
+		start := hunk.OldStart - 1
+		if hunk.OldLines == 0 {
+			start = hunk.OldStart
+		}
+		result = append(result, oldLines[next:start]...)
+
+		for _, line := range hunk.Lines {
+			if line.Kind == ' ' || (line.Kind == '+') == accepted[i] {
+				result = append(result, line.Text)
+			}
+		}
+		next = start + hunk.OldLines
+	}
+	result = append(result, oldLines[next:]...)
+
+	if len(result) == 0 {
+		return ""
+	}
+	return strings.Join(result, "\n") + "\n"
+}
+
+// Header is the @@ line of the hunk
+func (h DiffHunk) Header() string {
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+}
+
+// splitLines splits text into lines without their newline
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines computes a shortest edit script from the longest common
+// subsequence of the two line lists
+func diffLines(a, b []string) []DiffLine {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []DiffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, DiffLine{Kind: ' ', Text: a[i]})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			ops = append(ops, DiffLine{Kind: '+', Text: b[j]})
+			j++
+		default:
+			ops = append(ops, DiffLine{Kind: '-', Text: a[i]})
+			i++
+		}
+	}
+	return ops
+}
@@ -1,7 +1,104 @@
 package utils
 
-import "testing"
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
 
-func TestUtils(t *testing.T) {
-	t.Skip("Implementation pending")
+func TestDiff(t *testing.T) {
+	oldText := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"
+	newText := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+
+	hunks := Diff(oldText, newText, 3)
+	var headers []string
+	for _, hunk := range hunks {
+		headers = append(headers, hunk.Header())
+	}
+	if want := []string{"@@ -1,5 +1,5 @@", "@@ -9,3 +9,4 @@"}; !reflect.DeepEqual(headers, want) {
+		t.Fatalf("Diff() hunks = %v, want %v", headers, want)
+	}
+	if want := []DiffLine{{' ', "a"}, {'-', "b"}, {'+', "B"}, {' ', "c"}, {' ', "d"}, {' ', "e"}}; !reflect.DeepEqual(hunks[0].Lines, want) {
+		t.Errorf("Diff() first hunk = %v, want %v", hunks[0].Lines, want)
+	}
+
+	if hunks := Diff(oldText, oldText, 3); len(hunks) != 0 {
+		t.Errorf("Diff() of equal texts = %v, want no hunks", hunks)
+	}
+	if hunks := Diff("", "x\ny\n", 3); len(hunks) != 1 || hunks[0].Header() != "@@ -0,0 +1,2 @@" {
+		t.Errorf("Diff() against empty text = %+v", hunks)
+	}
+}
+
+func TestApplyHunks(t *testing.T) {
+	oldText := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"
+	newText := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+	hunks := Diff(oldText, newText, 3)
+
+	tests := []struct {
+		accepted []bool
+		want     string
+	}{
+		{[]bool{true, true}, newText},
+		{[]bool{false, false}, oldText},
+		{[]bool{true, false}, "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"},
+		{[]bool{false, true}, "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"},
+	}
+	for _, tt := range tests {
+		if got := ApplyHunks(oldText, hunks, tt.accepted); got != tt.want {
+			t.Errorf("ApplyHunks(%v) = %q, want %q", tt.accepted, got, tt.want)
+		}
+	}
+
+	if got := ApplyHunks("", Diff("", "x\n", 3), []bool{true}); got != "x\n" {
+		t.Errorf("ApplyHunks() on empty text = %q", got)
+	}
+}
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate func(string) error
+		valid    []string
+		invalid  []string
+	}{
+		{"ValidateProjectName", ValidateProjectName,
+			[]string{"api", "My_App-2", "v1.0"},
+			[]string{"", "my app", "$(shell rm)", "a:b", "-x", ".hidden"}},
+		{"ValidateDockerImage", ValidateDockerImage,
+			[]string{"app", "acme/app", "ghcr.io/acme/app:1.2", "localhost:5000/app", "app@sha256:" + strings.Repeat("a", 64), "my-org/my__app"},
+			[]string{"", "App", "acme//app", "app:", "app:-bad", "registry.io:port/app", "app@md5", "-app"}},
+		{"ValidateTargetName", ValidateTargetName,
+			[]string{"seed", "docs/index.html", "db-reset"},
+			[]string{"", ".PHONY", "-x", "a b", "a:b", "%.o", "$(X)"}},
+		{"ValidateVariableName", ValidateVariableName,
+			[]string{"ENV", "_x", "DB_URL2"},
+			[]string{"", "1ENV", "a-b", "a b"}},
+		{"ValidatePort", ValidatePort,
+			[]string{"1", "8080", "65535"},
+			[]string{"", "0", "65536", "http", "-1"}},
+		{"ValidateURL", ValidateURL,
+			[]string{"https://example.com", "postgres://localhost:5432/app?sslmode=disable"},
+			[]string{"", "example.com", "http://", "https://a b.com"}},
+		{"ValidateFilePath", ValidateFilePath,
+			[]string{"Makefile", "makegen.mk", "/tmp/out/GNUmakefile", "build/app.mk"},
+			[]string{"", "my file.mk", "out/", "a:b", "$(X).mk"}},
+	}
+	for _, tt := range tests {
+		for _, value := range tt.valid {
+			if err := tt.validate(value); err != nil {
+				t.Errorf("%s(%q) = %v, want nil", tt.name, value, err)
+			}
+		}
+		for _, value := range tt.invalid {
+			if err := tt.validate(value); err == nil {
+				t.Errorf("%s(%q) = nil, want an error", tt.name, value)
+			}
+		}
+	}
+
+	image, err := ParseDockerImage("registry.example.com:5000/team/app:v2")
+	if want := (DockerImage{Registry: "registry.example.com:5000", Repository: "team/app", Tag: "v2"}); err != nil || image != want {
+		t.Errorf("ParseDockerImage() = %+v, %v, want %+v", image, err, want)
+	}
 }
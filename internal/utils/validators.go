@@ -1,3 +1,177 @@
 package utils
 
-// TODO: Implement validators
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	projectNamePattern  = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	// Docker reference grammar, as in the distribution/reference package
+	imagePathPattern   = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*$`)
+	imageDomainPattern = regexp.MustCompile(`^(?:[A-Za-z0-9]|[A-Za-z0-9][A-Za-z0-9-]*[A-Za-z0-9])(?:\.(?:[A-Za-z0-9]|[A-Za-z0-9][A-Za-z0-9-]*[A-Za-z0-9]))*(?::[0-9]+)?$`)
+	imageTagPattern    = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+	imageDigestPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,}$`)
+)
+
+// makeSpecialChars are the characters make reads as syntax in a target or
+// file name
+const makeSpecialChars = ":;#=%$()|\\*?[]'\"`"
+
+// ValidateProjectName accepts names that are safe in PROJECT_NAME, binary
+// paths and image tags: letters, digits, '.', '_' and '-', starting with a
+// letter or digit
+func ValidateProjectName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("project name is required")
+	case len(name) > 128:
+		return errors.New("project name must be at most 128 characters")
+	case strings.ContainsAny(name, " \t"):
+		return errors.New("project name must not contain spaces")
+	case !projectNamePattern.MatchString(name):
+		return errors.New("project name may only contain letters, digits, '.', '_' and '-', and must start with a letter or digit")
+	}
+	return nil
+}
+
+// DockerImage is a parsed image reference:
+// [registry[:port]/]repository[:tag][@digest]
+type DockerImage struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseDockerImage splits an image reference and checks each part against
+// the rules Docker applies
+func ParseDockerImage(ref string) (DockerImage, error) {
+	var image DockerImage
+	if ref == "" {
+		return image, errors.New("image name is required")
+	}
+
+	name := ref
+	if at := strings.Index(name, "@"); at >= 0 {
+		name, image.Digest = name[:at], name[at+1:]
+		if !imageDigestPattern.MatchString(image.Digest) {
+			return image, fmt.Errorf("invalid digest %q: expected algorithm:hex, e.g. sha256:...", image.Digest)
+		}
+	}
+	// A colon after the last slash starts the tag; before it, a port
+	if colon := strings.LastIndex(name, ":"); colon > strings.LastIndex(name, "/") {
+		name, image.Tag = name[:colon], name[colon+1:]
+		if !imageTagPattern.MatchString(image.Tag) {
+			return image, fmt.Errorf("invalid tag %q: use up to 128 letters, digits, '_', '.' and '-', not starting with '.' or '-'", image.Tag)
+		}
+	}
+
+	parts := strings.Split(name, "/")
+	// The first part is a registry when it looks like a host name
+	if len(parts) > 1 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		image.Registry, parts = parts[0], parts[1:]
+		if !imageDomainPattern.MatchString(image.Registry) {
+			return image, fmt.Errorf("invalid registry %q", image.Registry)
+		}
+	}
+	for _, part := range parts {
+		switch {
+		case part == "":
+			return image, fmt.Errorf("invalid image name %q: empty path component", ref)
+		case strings.ToLower(part) != part:
+			return image, fmt.Errorf("invalid image name %q: repository names must be lowercase", ref)
+		case !imagePathPattern.MatchString(part):
+			return image, fmt.Errorf("invalid image name %q: use lowercase letters and digits separated by '.', '_', '__' or '-'", ref)
+		}
+	}
+	image.Repository = strings.Join(parts, "/")
+	if len(name) > 255 {
+		return image, errors.New("image name must be at most 255 characters")
+	}
+	return image, nil
+}
+
+// ValidateDockerImage accepts a Docker image reference
+func ValidateDockerImage(ref string) error {
+	_, err := ParseDockerImage(ref)
+	return err
+}
+
+// ValidateTargetName rejects names make would read as something else:
+// whitespace and separators split the rule, % makes it a pattern rule, a
+// leading dot a special target and a leading dash looks like an option
+func ValidateTargetName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("target name is required")
+	case strings.HasPrefix(name, "."):
+		return errors.New("target names starting with '.' are special to make")
+	case strings.HasPrefix(name, "-"):
+		return errors.New("target names must not start with '-'")
+	case strings.ContainsAny(name, " \t"):
+		return errors.New("target names must not contain spaces")
+	case strings.ContainsAny(name, makeSpecialChars):
+		return fmt.Errorf("target names must not contain any of %s", makeSpecialChars)
+	}
+	return nil
+}
+
+// ValidateVariableName accepts make variable names that are also valid
+// environment variable names
+func ValidateVariableName(name string) error {
+	if !variableNamePattern.MatchString(name) {
+		return errors.New("use letters, digits and underscores, not starting with a digit")
+	}
+	return nil
+}
+
+// ValidatePort accepts a TCP port number from 1 to 65535
+func ValidatePort(value string) error {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port %q: use a number from 1 to 65535", value)
+	}
+	return nil
+}
+
+// ValidateURL accepts an absolute URL with a scheme and a host, such as
+// https://example.com or postgres://localhost:5432/app
+func ValidateURL(value string) error {
+	u, err := url.Parse(value)
+	switch {
+	case err != nil:
+		return fmt.Errorf("invalid URL %q", value)
+	case u.Scheme == "":
+		return fmt.Errorf("invalid URL %q: missing scheme, e.g. https://", value)
+	case u.Host == "":
+		return fmt.Errorf("invalid URL %q: missing host", value)
+	case strings.ContainsAny(value, " \t"):
+		return fmt.Errorf("invalid URL %q: must not contain spaces", value)
+	}
+	return nil
+}
+
+// ValidateFilePath accepts a relative or absolute path make can use as a
+// target or prerequisite: no spaces and none of make's special characters
+func ValidateFilePath(path string) error {
+	switch {
+	case path == "":
+		return errors.New("path is required")
+	case strings.ContainsRune(path, 0):
+		return errors.New("path must not contain NUL bytes")
+	case strings.HasSuffix(path, "/"):
+		return fmt.Errorf("%s is a directory, not a file", path)
+	case strings.ContainsAny(path, " \t\n"):
+		return errors.New("make cannot handle paths with spaces")
+	case strings.ContainsAny(path, makeSpecialChars):
+		return fmt.Errorf("path must not contain any of %s", makeSpecialChars)
+	}
+	return nil
+}